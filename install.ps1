@@ -9,7 +9,7 @@
 #   irm https://github.com/reky0/glyph/releases/latest/download/install.ps1 | iex
 #
 # Parameters:
-#   -Tools   Comma-separated list of tools to install (pin, ask, diff, stand)
+#   -Tools   Comma-separated list of tools to install (pin, ask, diff, stand, glyph)
 #   -All     Install all tools without prompting
 #   -Version Specific release version to install (default: latest)
 #   -InstallDir  Directory to install binaries (default: %LOCALAPPDATA%\glyph\bin)
@@ -25,12 +25,13 @@ param(
 $ErrorActionPreference = "Stop"
 
 $REPO     = "reky0/glyph"
-$ALL_TOOLS = @("pin", "ask", "diff", "stand")
+$ALL_TOOLS = @("pin", "ask", "diff", "stand", "glyph")
 $DESC = @{
     pin   = "clipboard for URLs, commands, paths and notes"
     ask   = "ask an AI with automatic directory context"
     diff  = "explain a git diff in plain English"
     stand = "generate a standup from recent git commits"
+    glyph = "every tool above in one binary (glyph pin, glyph ask, ...)"
 }
 
 # ── colours ───────────────────────────────────────────────────────────────────
@@ -16,12 +16,13 @@
 set -euo pipefail
 
 REPO="reky0/glyph"
-TOOLS=(pin ask diff stand)
+TOOLS=(pin ask diff stand glyph)
 declare -A DESC=(
     [pin]="clipboard for URLs, commands, paths and notes"
     [ask]="ask an AI with automatic directory context"
     [diff]="explain a git diff in plain English"
     [stand]="generate a standup from recent git commits"
+    [glyph]="every tool above in one binary (glyph pin, glyph ask, ...)"
 )
 
 # ── colours ──────────────────────────────────────────────────────────────────
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+	mind "github.com/reky0/glyph-mind"
+)
+
+// apiKeyEnv names the environment variable that overrides api_key and
+// api_key_command.
+const apiKeyEnv = "GLYPH_API_KEY"
+
+// apiKeyTimeout bounds api_key_command, so a command stuck waiting for
+// input cannot hang the tool.
+const apiKeyTimeout = 10 * time.Second
+
+// resolveAPIKey sets cfg.APIKey from, in order, $GLYPH_API_KEY, the output
+// of api_key_command, or api_key as written, then resolves the keys of the
+// fallbacks (see resolveFallbackKeys). The command only runs when the
+// provider needs a key, so an Ollama setup never waits on it.
+func resolveAPIKey(cfg *core.Config) error {
+	if key := os.Getenv(apiKeyEnv); key != "" {
+		cfg.APIKey = key
+	} else if cfg.APIKeyCommand != "" && mind.RequiresAPIKey(cfg.AIProvider) {
+		key, err := runKeyCommand(cfg.APIKeyCommand)
+		if err != nil {
+			return err
+		}
+		cfg.APIKey = key
+	}
+	return resolveFallbackKeys(cfg)
+}
+
+// resolveFallbackKeys resolves the keys of the fallback providers with
+// ResolveProviderKeys.
+func resolveFallbackKeys(cfg *core.Config) error {
+	providers := make([]string, len(cfg.Fallbacks))
+	for i, spec := range cfg.Fallbacks {
+		providers[i], _, _ = strings.Cut(spec, ":")
+	}
+	return ResolveProviderKeys(cfg, providers...)
+}
+
+// ResolveProviderKeys sets api_keys.<provider> for each of providers that
+// needs a key, other than ai_provider, from $GLYPH_API_KEY_<PROVIDER> or
+// the output of api_key_commands.<provider>, or leaves it as written.
+// LoadConfig does this for the fallbacks; tools that call other providers,
+// such as ask --compare, do it for theirs.
+func ResolveProviderKeys(cfg *core.Config, providers ...string) error {
+	primary := strings.ToLower(cfg.AIProvider)
+	if primary == "" {
+		primary = "groq"
+	}
+	done := map[string]bool{primary: true}
+	for _, name := range providers {
+		name = strings.ToLower(name)
+		if name == "" {
+			name = "groq"
+		}
+		if done[name] || !mind.RequiresAPIKey(name) {
+			continue
+		}
+		done[name] = true
+		key := os.Getenv(apiKeyEnv + "_" + strings.ToUpper(name))
+		if command := cfg.APIKeyCommands[name]; key == "" && command != "" {
+			var err error
+			if key, err = runKeyCommand(command); err != nil {
+				return err
+			}
+		}
+		if key != "" {
+			if cfg.APIKeys == nil {
+				cfg.APIKeys = map[string]string{}
+			}
+			cfg.APIKeys[name] = key
+		}
+	}
+	return nil
+}
+
+// runKeyCommand runs command through the shell and returns its trimmed
+// stdout. What the command prints on stderr becomes the error.
+func runKeyCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var out, errBuf bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &errBuf
+	// Killing the shell leaves its children, such as a stuck gpg, holding
+	// the pipes open; stop waiting for them shortly after.
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "", &core.AppError{Msg: fmt.Sprintf("api_key_command %q timed out after %s", command, apiKeyTimeout)}
+	case err != nil:
+		if msg := strings.TrimSpace(errBuf.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return "", &core.AppError{Msg: fmt.Sprintf("api_key_command %q failed", command), Err: err}
+	}
+	key := strings.TrimSpace(out.String())
+	if key == "" {
+		return "", &core.AppError{Msg: fmt.Sprintf("api_key_command %q printed no key", command)}
+	}
+	return key, nil
+}
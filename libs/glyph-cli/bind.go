@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// bindings holds, per command, the viper keys to bind to its persistent
+// flags. They are bound when the command or one of its subcommands runs
+// rather than at init time, so several tools can share one process (as in
+// the glyph binary) without overwriting each other's viper keys.
+var bindings = map[*cobra.Command]map[string]string{}
+
+// BindFlags binds viper keys to cmd's persistent flags, keyed by viper key,
+// for any run of cmd or its subcommands. It panics if a flag does not exist.
+func BindFlags(cmd *cobra.Command, keys map[string]string) {
+	for _, flag := range keys {
+		if cmd.PersistentFlags().Lookup(flag) == nil {
+			panic(fmt.Sprintf("failed to bind %s flag: no such persistent flag", flag))
+		}
+	}
+	if _, ok := bindings[cmd]; !ok {
+		bindings[cmd] = map[string]string{}
+		installBindHook(cmd)
+	}
+	for key, flag := range keys {
+		bindings[cmd][key] = flag
+	}
+}
+
+// installBindHook wraps cmd's PersistentPreRunE so the bindings are applied
+// before anything reads viper.
+func installBindHook(cmd *cobra.Command) {
+	prev := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		for key, flag := range bindings[cmd] {
+			if err := viper.BindPFlag(key, cmd.PersistentFlags().Lookup(flag)); err != nil {
+				return fmt.Errorf("failed to bind %s flag: %w", flag, err)
+			}
+		}
+		if Raw() {
+			viper.Set("style", "raw")
+		}
+		if prev != nil {
+			return prev(c, args)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+)
+
+// debugEnv names the environment variable that turns on diagnostics on
+// stderr, such as which provider served each request.
+const debugEnv = "GLYPH_DEBUG"
+
+// DebugEnabled reports whether GLYPH_DEBUG asks for diagnostics.
+func DebugEnabled() bool {
+	on, _ := strconv.ParseBool(os.Getenv(debugEnv))
+	return on
+}
+
+// NewClient builds the AI client for cfg. A model name that matches no
+// known provider is only a warning on stderr, since providers add models
+// faster than glyph learns their names, as are settings the provider
+// ignores (see mind.UnsupportedFeatures).
+func NewClient(cfg core.Config) (mind.Client, error) {
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	if err := mind.CheckModel(cfg.AIProvider, mind.ResolveModel(cfg)); errors.Is(err, mind.ErrUnrecognizedModel) {
+		fmt.Fprintln(os.Stderr, theme.Muted("warning: "+err.Error()))
+	}
+	for _, err := range mind.UnsupportedFeatures(cfg) {
+		fmt.Fprintln(os.Stderr, theme.Muted("warning: "+err.Error()))
+	}
+	client, err := mind.NewClientFromConfig(cfg)
+	if err != nil || !DebugEnabled() {
+		return client, err
+	}
+	return debugClient{Client: client, theme: theme}, nil
+}
+
+// debugClient reports on stderr which provider and model answered, and
+// how large the request was. Its streams record StreamStats for
+// PrintStats.
+type debugClient struct {
+	mind.Client
+	theme ink.Theme
+}
+
+func (c debugClient) Stream(ctx context.Context, system, user string) (*mind.Stream, error) {
+	stream, err := c.Client.Stream(ctx, system, user)
+	if err == nil {
+		fmt.Fprintln(os.Stderr, c.theme.Muted(fmt.Sprintf("debug: served by %s (%s), request body %s",
+			stream.Provider(), stream.Model(), formatSize(int64(stream.RequestBytes())))))
+		stream = mind.WithStats(stream)
+	}
+	return stream, err
+}
+
+// PrintStats prints how stream, which must be fully drained, streamed:
+// the time to the first chunk and in total, the number of chunks, the
+// output tokens and the tokens per second. Only streams from a client
+// made with GLYPH_DEBUG set record these, so it prints nothing otherwise.
+func PrintStats(cfg core.Config, stream *mind.Stream) {
+	stats, ok := stream.Stats()
+	if !ok {
+		return
+	}
+	approx := ""
+	if stats.Estimated {
+		approx = "~"
+	}
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	fmt.Fprintln(os.Stderr, theme.Muted(fmt.Sprintf("debug: %d chunks in %s (first after %s), %s%d tokens, %.1f tokens/s",
+		stats.Chunks, stats.Elapsed.Round(time.Millisecond), stats.FirstChunk.Round(time.Millisecond),
+		approx, stats.Tokens, stats.TokensPerSecond())))
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// CompletionCommand returns a "completion" subcommand that writes the shell
+// completion script for the root of the command tree it is added to, so
+// the same helper works for the standalone tools and the glyph binary.
+func CompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate a shell completion script",
+		Long: `Generate a shell completion script and print it to stdout.
+
+Load it in the current shell, for example:
+
+  source <(pin completion bash)
+  pin completion fish | source
+
+or write it to your shell's completion directory to load it in every session.`,
+		Args:                  cobra.ExactArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return WriteCompletion(cmd.Root(), args[0], cmd.OutOrStdout())
+		},
+	}
+}
+
+// WriteCompletion writes the completion script of root for shell: bash,
+// zsh, fish or powershell. Tools that take free-form arguments, such as
+// ask, have no completion subcommand and call it for a --completion flag.
+func WriteCompletion(root *cobra.Command, shell string, out io.Writer) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(out, true)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(out)
+	}
+	return fmt.Errorf("unsupported shell %q (valid: bash, zsh, fish, powershell)", shell)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AddAIFlags registers the persistent flags shared by the AI tools: the
+// --model and --provider overrides, the streaming output options
+// (--light-markdown, --flush-interval, --trim, --wrap), --stop, --seed, the
+// structured output options (--response-format, --json-schema), --strict
+// and --timeout.
+func AddAIFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("model", "", "Override ai_model for this run")
+	cmd.PersistentFlags().String("provider", "", "Override ai_provider for this run: "+strings.Join(mind.KnownProviders(), ", "))
+	cmd.PersistentFlags().Bool("light-markdown", false, "Style **bold**, *italic* and `code` in streamed output")
+	cmd.PersistentFlags().Duration("flush-interval", 0, "Coalesce streamed output, flushing at most this often, e.g. 16ms (0 flushes every chunk)")
+	cmd.PersistentFlags().Bool("trim", false, "Strip leading and trailing whitespace from the answer")
+	cmd.PersistentFlags().Bool("wrap", false, "Word-wrap the streamed answer at the terminal width")
+	cmd.PersistentFlags().StringArray("stop", nil, "Stop generating at this sequence; repeat for several (replaces stop from the config)")
+	cmd.PersistentFlags().Int64("seed", 0, "Sample with this seed for reproducible answers, where the provider supports it (overrides seed from the config)")
+	cmd.PersistentFlags().String("response-format", "", "Ask for a \"json\" answer instead of \"text\"; fails if the answer is not valid JSON")
+	cmd.PersistentFlags().String("json-schema", "", "Path of a JSON schema the answer must follow (implies --response-format json)")
+	cmd.PersistentFlags().Bool("strict", false, "Fail instead of warning when the prompt likely exceeds the model's context window")
+	cmd.PersistentFlags().Duration("timeout", 0, "Give up on a response after this long, e.g. 2m (0 means no limit)")
+	BindFlags(cmd, map[string]string{
+		"model":           "model",
+		"provider":        "provider",
+		"light_markdown":  "light-markdown",
+		"flush_interval":  "flush-interval",
+		"trim":            "trim",
+		"wrap":            "wrap",
+		"stop":            "stop",
+		"seed":            "seed",
+		"response_format": "response-format",
+		"json_schema":     "json-schema",
+		"strict":          "strict",
+		"timeout":         "timeout",
+	})
+}
+
+// LoadConfig loads the glyph config and applies the per-run overrides from
+// the --style, --model, --provider, --stop, --seed, --response-format and
+// --json-schema flags. The API key comes from $GLYPH_API_KEY when set,
+// then from api_key_command, then from api_key.
+func LoadConfig() (core.Config, error) {
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return cfg, err
+	}
+	if style := viper.GetString("style"); style != "" {
+		cfg.DefaultStyle = style
+	}
+	if provider := viper.GetString("provider"); provider != "" {
+		if !mind.IsKnownProvider(provider) {
+			return cfg, &core.AppError{
+				Msg: fmt.Sprintf("unknown provider %q (valid: %s)", provider, strings.Join(mind.KnownProviders(), ", ")),
+			}
+		}
+		cfg.AIProvider = provider
+	}
+	if model := viper.GetString("model"); model != "" {
+		// An explicit --model is never swapped for the provider's default,
+		// so reject one that belongs to another provider up front.
+		if err := mind.CheckModel(cfg.AIProvider, model); err != nil && !errors.Is(err, mind.ErrUnrecognizedModel) {
+			return cfg, err
+		}
+		cfg.AIModel = model
+	}
+	if stop := viper.GetStringSlice("stop"); len(stop) > 0 {
+		cfg.Stop = stop
+	}
+	if viper.IsSet("seed") {
+		seed := viper.GetInt64("seed")
+		cfg.Seed = &seed
+	}
+	if format := viper.GetString("response_format"); format != "" {
+		cfg.ResponseFormat = format
+	}
+	if schema := viper.GetString("json_schema"); schema != "" {
+		cfg.JSONSchema = schema
+	}
+	if err := resolveAPIKey(&cfg); err != nil {
+		return cfg, err
+	}
+	ApplyTheme(cfg)
+	return cfg, nil
+}
+
+// ApplyTheme applies the [theme] options of cfg to every theme. LoadConfig
+// calls it; tools that do not otherwise need the config can call it alone.
+func ApplyTheme(cfg core.Config) {
+	ink.SetSymbols(cfg.Theme.Symbols)
+	ink.SetBorderColor(cfg.Theme.Border)
+}
+
+// RequestContext derives the context for one AI request from parent,
+// applying the --timeout flag when it is set.
+func RequestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+	return context.WithCancel(parent)
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ConfigCommand returns a "config" command whose "check" subcommand reports
+// mistakes in the config file along with how to fix them.
+func ConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the glyph config file",
+	}
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "check",
+		Short: "Check the config file and suggest fixes",
+		Long: `Check the config file for invalid values and missing settings, and
+suggest a fix for each problem, such as the provider or style a misspelled
+name was likely meant to be. Exits with status 1 if there are errors;
+warnings alone do not fail.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := CheckConfig(cmd.OutOrStdout())
+			// The problems are already printed; the usage adds nothing.
+			cmd.SilenceUsage, cmd.SilenceErrors = err != nil, err != nil
+			return err
+		},
+	})
+	return configCmd
+}
+
+// CheckConfig prints the problems found in the config file to out, each
+// with how to fix it, and returns an error naming how many of them are
+// errors rather than warnings. Tools without a config command, such as
+// ask, call it for their --config-check flag.
+func CheckConfig(out io.Writer) error {
+	theme := ink.ThemeFrom(viper.GetString("style"))
+	path, err := core.ConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := core.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	problems := checkConfig(cfg, path)
+	errs := 0
+	for _, p := range problems {
+		if p.warn {
+			fmt.Fprintln(out, theme.Warn(p.msg))
+			continue
+		}
+		errs++
+		fmt.Fprintln(out, theme.Error(p.msg))
+	}
+	switch {
+	case errs > 0:
+		return &core.AppError{Msg: fmt.Sprintf("%d %s in %s", errs, pluralize(errs, "error", "errors"), path)}
+	case len(problems) == 0:
+		fmt.Fprintln(out, theme.Success(path+" looks good"))
+	}
+	return nil
+}
+
+// configProblem is one finding of checkConfig.
+type configProblem struct {
+	warn bool // a warning rather than an error
+	msg  string
+}
+
+// checkConfig returns the problems found in cfg, read from path.
+func checkConfig(cfg core.Config, path string) []configProblem {
+	var problems []configProblem
+	fail := func(format string, a ...any) {
+		problems = append(problems, configProblem{msg: fmt.Sprintf(format, a...)})
+	}
+	warn := func(format string, a ...any) {
+		problems = append(problems, configProblem{warn: true, msg: fmt.Sprintf(format, a...)})
+	}
+
+	provider := cfg.AIProvider
+	if provider == "" {
+		provider = "groq"
+	}
+	keyErr := resolveAPIKey(&cfg)
+	switch {
+	case !mind.IsKnownProvider(provider):
+		fail("ai_provider %q is invalid%s", provider, didYouMean(provider, mind.KnownProviders()))
+	case keyErr != nil:
+		fail("%v", keyErr)
+	case mind.RequiresAPIKey(provider) && mind.APIKeyFor(cfg, provider) == "":
+		fail("no api_key set for %s — add api_key = \"...\" or api_key_command to %s, or set ai_provider = \"ollama\" to run models locally", provider, path)
+	case cfg.AIModel != "":
+		if err := mind.CheckModel(provider, cfg.AIModel); errors.Is(err, mind.ErrUnrecognizedModel) {
+			warn("ai_model %q is not a model glyph knows for %s; check the name if requests fail", cfg.AIModel, provider)
+		} else if err != nil {
+			warn("%v; %s is used instead", err, mind.ResolveModel(cfg))
+		}
+	}
+
+	for _, spec := range cfg.Fallbacks {
+		name, _, _ := strings.Cut(spec, ":")
+		if !mind.IsKnownProvider(name) {
+			fail("fallback %q: provider %q is invalid%s", spec, name, didYouMean(name, mind.KnownProviders()))
+		}
+	}
+
+	for _, err := range mind.UnsupportedFeatures(cfg) {
+		warn("%v", err)
+	}
+
+	if style := strings.ToLower(cfg.DefaultStyle); style != "" && !slices.Contains(ink.ThemeNames(), style) {
+		warn("default_style %q is unknown, so rounded is used%s", cfg.DefaultStyle, didYouMean(cfg.DefaultStyle, ink.ThemeNames()))
+	}
+
+	// Whatever else the client rejects, such as an invalid system_role or
+	// an unreadable json_schema, once the basics above are right.
+	if allWarnings(problems) {
+		if _, err := mind.NewClientFromConfig(cfg); err != nil {
+			fail("%v", err)
+		}
+	}
+	return problems
+}
+
+// didYouMean returns " — did you mean "x"?" for the option closest to s,
+// or the list of valid options when none is close.
+func didYouMean(s string, options []string) string {
+	if near, ok := core.Suggest(s, options); ok {
+		return fmt.Sprintf(" — did you mean %q?", near)
+	}
+	return " (valid: " + strings.Join(options, ", ") + ")"
+}
+
+// allWarnings reports whether problems holds no errors.
+func allWarnings(problems []configProblem) bool {
+	for _, p := range problems {
+		if !p.warn {
+			return false
+		}
+	}
+	return true
+}
+
+// pluralize returns one when n is 1 and many otherwise.
+func pluralize(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/viper"
+)
+
+// contextHeadroom is the share of the context window a prompt may use
+// before CheckPromptSize complains; the rest is left for the answer.
+const contextHeadroom = 0.9
+
+// CheckPromptSize estimates the size of a request and warns on stderr when
+// it likely exceeds the model's context window. With --strict it returns an
+// error instead, so the request is never sent. Models with an unknown
+// context size are not checked.
+func CheckPromptSize(cfg core.Config, system, user string) error {
+	model := mind.ResolveModel(cfg)
+	window := mind.ContextWindow(cfg.AIProvider, model)
+	if window == 0 {
+		return nil
+	}
+	tokens := mind.EstimateTokens(system) + mind.EstimateTokens(user)
+	if float64(tokens) <= float64(window)*contextHeadroom {
+		return nil
+	}
+
+	msg := fmt.Sprintf("prompt is about %d tokens, which likely exceeds the %d-token context window of %s", tokens, window, model)
+	if viper.GetBool("strict") {
+		return &core.AppError{Msg: msg}
+	}
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	fmt.Fprintln(os.Stderr, theme.Muted("warning: "+msg))
+	return nil
+}
+
+// ReadInput reads r, at most cfg.MaxInputBytes of it, so piping a huge file
+// by accident does not send it all. When r holds more, the rest is left
+// unread and a warning naming what (such as "piped input") is printed on
+// stderr. A limit of 0 or less reads everything.
+func ReadInput(r io.Reader, cfg core.Config, what string) (string, error) {
+	limit := cfg.MaxInputBytes
+	if limit <= 0 {
+		data, err := io.ReadAll(r)
+		return string(data), err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) <= limit {
+		return string(data), nil
+	}
+
+	data = data[:limit]
+	// Do not end on half a multi-byte character.
+	for i := 1; i < utf8.UTFMax && len(data) > 0; i++ {
+		if r, size := utf8.DecodeLastRune(data); r != utf8.RuneError || size > 1 {
+			break
+		}
+		data = data[:len(data)-1]
+	}
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	fmt.Fprintln(os.Stderr, theme.Muted(fmt.Sprintf(
+		"warning: %s is larger than %s; only the first %s is sent (raise max_input_bytes to send more)",
+		what, formatSize(limit), formatSize(limit))))
+	return string(data), nil
+}
+
+// WarnTruncated prints a warning on stderr when stream, which must be fully
+// drained, was cut off at a length limit instead of ending normally.
+func WarnTruncated(cfg core.Config, stream *mind.Stream) {
+	if !stream.Truncated() {
+		return
+	}
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	fmt.Fprintln(os.Stderr, theme.Muted("warning: the answer was cut off at the model's length limit (output tokens or context window)"))
+}
+
+// WarnEmpty prints a warning on stderr when stream, which must be fully
+// drained, ended without error but output, the text it delivered, has
+// nothing visible in it. A response withheld by the provider's content
+// filter is named as such.
+func WarnEmpty(cfg core.Config, stream *mind.Stream, output string) {
+	if strings.TrimSpace(output) != "" {
+		return
+	}
+	msg := "warning: the model returned an empty response"
+	if stream.Filtered() {
+		msg += " (withheld by the provider's content filter)"
+	}
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	fmt.Fprintln(os.Stderr, theme.Muted(msg))
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	ink "github.com/reky0/glyph-ink"
+)
+
+// ExitInterrupted is the exit status of a tool stopped with Ctrl-C,
+// following the shell convention of 128 + SIGINT.
+const ExitInterrupted = 130
+
+// InterruptContext returns a context that is canceled on the first Ctrl-C,
+// so a streaming request ends cleanly and the tool can keep the partial
+// answer. Call stop once streaming is over to restore the default handling,
+// letting a second Ctrl-C kill the process. Unlike signal.NotifyContext,
+// stop does not cancel the context, so ctx.Err() afterwards still tells
+// whether the user interrupted.
+func InterruptContext(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(sig)
+			close(done)
+		})
+	}
+}
+
+// ExitInterrupt prints an "(interrupted)" note to stderr and exits with
+// ExitInterrupted.
+func ExitInterrupt(theme ink.Theme) {
+	fmt.Fprintln(os.Stderr, theme.Muted("(interrupted)"))
+	os.Exit(ExitInterrupted)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ModelsCommand returns a "models" subcommand that lists the models offered
+// by the configured AI provider.
+func ModelsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "models",
+		Short: "List models available from the configured provider",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			models, err := mind.ListModels(cmd.Context(), cfg)
+			if err != nil {
+				return err
+			}
+			sort.Slice(models, func(i, j int) bool {
+				return models[i].Name < models[j].Name
+			})
+
+			theme := ink.ThemeFrom(viper.GetString("style"))
+			if len(models) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), theme.Muted("No models found."))
+				return nil
+			}
+			tbl := theme.Table().Headers("MODEL", "SIZE", "MODIFIED")
+			for _, m := range models {
+				modified := "-"
+				if !m.ModifiedAt.IsZero() {
+					modified = m.ModifiedAt.Format(time.DateOnly)
+				}
+				tbl.Row(m.Name, formatSize(m.Size), modified)
+			}
+			tbl.Render(cmd.OutOrStdout())
+			return nil
+		},
+	}
+}
+
+// formatSize renders a byte count in human units, or "-" when unknown.
+func formatSize(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"fmt"
+
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AddPagerFlag registers the persistent --no-pager flag, which turns off
+// the paging done by Page.
+func AddPagerFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool("no-pager", false, "Print long output directly instead of through $PAGER")
+	BindFlags(cmd, map[string]string{"no_pager": "no-pager"})
+}
+
+// Page prints long output such as a table, through $PAGER when stdout is
+// a terminal and the output is taller than it (see ink.Page), or directly
+// with --no-pager.
+func Page(s string) {
+	if viper.GetBool("no_pager") {
+		fmt.Print(s)
+		return
+	}
+	ink.Page(s)
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"io"
+
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/viper"
+)
+
+// NewStreamPrinter returns a StreamPrinter writing to w, configured from
+// the output flags registered by AddAIFlags and AddRawFlag.
+func NewStreamPrinter(w io.Writer) *ink.StreamPrinter {
+	p := ink.NewStreamPrinter(w)
+	p.LightMarkdown = viper.GetBool("light_markdown") && !Raw()
+	p.FlushInterval = viper.GetDuration("flush_interval")
+	p.TrimOutput = viper.GetBool("trim")
+	if viper.GetBool("wrap") {
+		// 0 when stdout is not a terminal, which leaves wrapping off.
+		p.Wrap = ink.TerminalWidth()
+	}
+	return p
+}
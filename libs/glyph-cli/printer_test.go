@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reky0/glyph-mind/mindtest"
+	"github.com/spf13/viper"
+)
+
+func TestStreamPrinterOverClient(t *testing.T) {
+	viper.Set("trim", true)
+	t.Cleanup(viper.Reset)
+
+	client := mindtest.NewStaticClient([]string{"\n\n  ", "Use `git log`", " to list commits.", "\n\n"})
+	stream, err := client.Stream(t.Context(), "system", "how do I list commits?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	text, err := NewStreamPrinter(&out).PrintStream(stream.C)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := "Use `git log` to list commits."
+	if text != want || out.String() != want+"\n" {
+		t.Errorf("text %q, printed %q; want %q trimmed", text, out.String(), want)
+	}
+	if calls := client.Calls(); len(calls) != 1 || calls[0].User != "how do I list commits?" {
+		t.Errorf("Calls = %+v, want the one question", calls)
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AddQuietFlag registers the persistent --quiet (-q) flag, which silences
+// the status lines, tips and notes written with Note. Errors and warnings
+// are still printed, so a failing cron job is never silent.
+func AddQuietFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP("quiet", "q", false, "Hide status lines, tips and notes on stderr; errors and warnings still show")
+	BindFlags(cmd, map[string]string{"quiet": "quiet"})
+}
+
+// Quiet reports whether --quiet is in effect.
+func Quiet() bool {
+	return viper.GetBool("quiet")
+}
+
+// Note prints a decorative message, such as "saved to ..." or a usage
+// tip, in muted style on stderr unless --quiet is set.
+func Note(theme ink.Theme, msg string) {
+	if Quiet() {
+		return
+	}
+	fmt.Fprintln(os.Stderr, theme.Muted(msg))
+}
+
+// StartSpinner shows an animated status line on stderr with messages (see
+// ink.NewSpinner) while a request is waiting for its first chunk. It shows
+// nothing, returning nil, when stderr is not a terminal or with --quiet or
+// --raw. Pass the result to the stream printer's Spinner field, and call
+// its Stop before printing an error; Stop is safe on nil.
+func StartSpinner(theme ink.Theme, messages ...string) *ink.Spinner {
+	if Quiet() || Raw() || !stderrIsTerminal() {
+		return nil
+	}
+	spin := ink.NewSpinner(os.Stderr, messages...)
+	spin.Style = theme.Muted
+	style := theme.Spinner()
+	spin.Frames, spin.FrameStyle = style.Frames, style.Style
+	return spin.Start()
+}
+
+// stderrIsTerminal reports whether stderr is attached to a terminal.
+func stderrIsTerminal() bool {
+	stat, err := os.Stderr.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AddRawFlag registers the persistent --raw flag. When set, the raw theme
+// replaces --style and default_style for the run, and decorative output
+// (tips, status lines, screen clearing, markdown styling) is skipped so only
+// the content remains.
+func AddRawFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool("raw", false, "Plain output for piping: no colors, symbols, borders or tips")
+	BindFlags(cmd, map[string]string{"raw": "raw"})
+}
+
+// Raw reports whether --raw is in effect.
+func Raw() bool {
+	return viper.GetBool("raw")
+}
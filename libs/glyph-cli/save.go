@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+)
+
+// SaveOutput writes text to path for the --save flag, creating parent
+// directories as needed and ending the file with a newline. Styling is
+// stripped (see ink.StripANSI), so the file holds plain text whatever the
+// terminal showed.
+func SaveOutput(path, text string) error {
+	text = ink.StripANSI(text)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return &core.AppError{Msg: "cannot create directory for " + path, Err: err}
+		}
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		return &core.AppError{Msg: "cannot save output to " + path, Err: err}
+	}
+	return nil
+}
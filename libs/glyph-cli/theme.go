@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+)
+
+// ThemeCommand returns a "theme" command whose "preview" subcommand renders
+// sample output in every registered theme, to help pick a --style or
+// default_style.
+func ThemeCommand() *cobra.Command {
+	themeCmd := &cobra.Command{
+		Use:   "theme",
+		Short: "Inspect output styles",
+	}
+	themeCmd.AddCommand(&cobra.Command{
+		Use:   "preview [style...]",
+		Short: "Show sample output in each style",
+		Long: `Show a header, status lines, badges and a small table in each style,
+or only in the styles named as arguments.`,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return ink.ThemeNames(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := args
+			if len(names) == 0 {
+				names = ink.ThemeNames()
+			}
+			for _, name := range names {
+				if !slices.Contains(ink.ThemeNames(), strings.ToLower(name)) {
+					return &core.AppError{Msg: fmt.Sprintf("unknown style %q (valid: %s)", name, strings.Join(ink.ThemeNames(), ", "))}
+				}
+			}
+			// Show the [theme] options from the config, if it can be read.
+			if cfg, err := core.LoadConfig(); err == nil {
+				ApplyTheme(cfg)
+			}
+			out := cmd.OutOrStdout()
+			for i, name := range names {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				previewTheme(out, name)
+			}
+			return nil
+		},
+	})
+	return themeCmd
+}
+
+// previewTheme writes the sample output for the theme called name.
+func previewTheme(out io.Writer, name string) {
+	theme := ink.ThemeFrom(name)
+	title := name
+	if name == "auto" {
+		title += " (" + ink.DetectedTheme() + " here)"
+	}
+	fmt.Fprintln(out, theme.Header(title))
+	fmt.Fprintln(out, theme.Success("saved to notes.md"))
+	fmt.Fprintln(out, theme.Warn("prompt is close to the context window"))
+	fmt.Fprintln(out, theme.Error("api_key is required for groq provider"))
+	fmt.Fprintln(out, theme.Muted("served by groq (llama-3.3-70b-versatile)"))
+	fmt.Fprintln(out, theme.Badge("badge")+" "+theme.Link("glyph", "https://github.com/reky0/glyph"))
+	spinner := theme.Spinner()
+	frames := strings.Join(spinner.Frames, " ")
+	if spinner.Style != nil {
+		frames = spinner.Style(frames)
+	}
+	fmt.Fprintln(out, frames+" "+theme.Muted("thinking…"))
+
+	tbl := theme.Table().Headers("ID", "TYPE", "TEXT")
+	tbl.Row("1792a3f0", theme.Badge("cmd"), "git log --oneline")
+	tbl.Row("1792a3f4", theme.Badge("url"), "https://go.dev")
+	tbl.Render(out)
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	store "github.com/reky0/glyph-store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// transcriptEnv names the environment variable that turns on transcript
+// logging of every AI call.
+const transcriptEnv = "GLYPH_TRANSCRIPT"
+
+// Transcript is one logged AI call. Prompts and responses are stored
+// verbatim, so the file is written with mode 0600.
+type Transcript struct {
+	store.Entry
+	Tool     string     `json:"tool"`
+	Provider string     `json:"provider"`
+	Model    string     `json:"model"`
+	System   string     `json:"system"`
+	User     string     `json:"user"`
+	Response string     `json:"response"`
+	Usage    mind.Usage `json:"usage"`
+	// Truncated is set when the provider cut the response off at a
+	// length limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TranscriptEnabled reports whether GLYPH_TRANSCRIPT asks for logging.
+func TranscriptEnabled() bool {
+	on, _ := strconv.ParseBool(os.Getenv(transcriptEnv))
+	return on
+}
+
+// openTranscripts returns the transcript log. It is a JSON Lines file, so
+// recording a call appends one line instead of rewriting every transcript
+// before it. A transcripts.json array left by older versions is moved into
+// it first.
+func openTranscripts() (*store.Store[Transcript], error) {
+	dir, err := core.NewPaths("transcript").DataDir()
+	if err != nil {
+		return nil, err
+	}
+	s := store.NewJSONLStore[Transcript](filepath.Join(dir, "transcripts.jsonl")).WithPerm(0o600)
+	if err := migrateTranscripts(filepath.Join(dir, "transcripts.json"), s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrateTranscripts moves the transcripts in the JSON array file at old,
+// if there is one, to the front of s and removes old.
+func migrateTranscripts(old string, s *store.Store[Transcript]) error {
+	if _, err := os.Stat(old); err != nil {
+		return nil
+	}
+	items, err := store.NewStore[Transcript](old).Load()
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		newer, err := s.Load()
+		if err != nil {
+			return err
+		}
+		if err := s.Save(append(items, newer...)); err != nil {
+			return err
+		}
+	}
+	return os.Remove(old)
+}
+
+// RecordTranscript logs a finished call when transcripts are enabled. The
+// stream must be fully drained. A failure to log is reported as a warning
+// on stderr and never fails the command.
+func RecordTranscript(tool string, cfg core.Config, system, user string, stream *mind.Stream, response string) {
+	if !TranscriptEnabled() {
+		return
+	}
+	provider := stream.Provider()
+	if provider == "" {
+		provider = cfg.AIProvider
+	}
+	t := Transcript{
+		Entry:    store.NewEntry(),
+		Tool:     tool,
+		Provider: provider,
+		Model:    stream.Model(),
+		System:   system,
+		User:     user,
+		Response: response,
+		Usage:    stream.Usage(),
+
+		Truncated: stream.Truncated(),
+	}
+	s, err := openTranscripts()
+	if err == nil {
+		err = s.Append(t)
+	}
+	if err != nil {
+		theme := ink.ThemeFrom(cfg.DefaultStyle)
+		fmt.Fprintln(os.Stderr, theme.Muted("warning: cannot write transcript: "+err.Error()))
+	}
+}
+
+// HistoryCommand returns a "history" subcommand that lists the transcripts
+// recorded with GLYPH_TRANSCRIPT=1, newest first.
+func HistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "history",
+		Aliases: []string{"log"},
+		Short:   "List logged AI calls (enable with GLYPH_TRANSCRIPT=1)",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			limit, _ := cmd.Flags().GetInt("limit")
+			tool, _ := cmd.Flags().GetString("tool")
+
+			s, err := openTranscripts()
+			if err != nil {
+				return err
+			}
+			items, err := s.Load()
+			if err != nil {
+				return err
+			}
+
+			theme := ink.ThemeFrom(viper.GetString("style"))
+			tbl := theme.Table().Headers("DATE", "TOOL", "PROVIDER", "MODEL", "TOKENS", "PROMPT")
+			shown := 0
+			for i := len(items) - 1; i >= 0; i-- {
+				t := items[i]
+				if tool != "" && t.Tool != tool {
+					continue
+				}
+				if limit > 0 && shown == limit {
+					break
+				}
+				tokens := "-"
+				if t.Usage.Total() > 0 {
+					tokens = fmt.Sprintf("%d/%d", t.Usage.InputTokens, t.Usage.OutputTokens)
+				}
+				tbl.Row(
+					t.CreatedAt.Local().Format(time.DateTime),
+					t.Tool,
+					t.Provider,
+					t.Model,
+					tokens,
+					oneLine(t.User, 50),
+				)
+				shown++
+			}
+			if shown == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), theme.Muted("No transcripts found. Set "+transcriptEnv+"=1 to record them."))
+				return nil
+			}
+			tbl.Render(cmd.OutOrStdout())
+			return nil
+		},
+	}
+	cmd.Flags().Int("limit", 20, "Show at most this many entries (0 for all)")
+	cmd.Flags().String("tool", "", "Only show calls made by this tool: ask, diff, stand")
+	return cmd
+}
+
+// oneLine collapses whitespace in s and truncates it to n runes.
+func oneLine(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+	mind "github.com/reky0/glyph-mind"
+	store "github.com/reky0/glyph-store"
+)
+
+// record logs a call asking user, as a tool does once the answer is in.
+func record(user string) {
+	ch := make(chan string)
+	close(ch)
+	stream := mind.NewStream(ch)
+	for range stream.C {
+	}
+	RecordTranscript("ask", core.Config{AIProvider: "ollama"}, "system", user, stream, "answer")
+}
+
+func TestRecordTranscriptAppends(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv(transcriptEnv, "1")
+
+	// A log written by an older version, as one JSON array.
+	dir, err := core.NewPaths("transcript").DataDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(dir, "transcripts.json")
+	if err := store.NewStore[Transcript](old).Save([]Transcript{{Entry: store.NewEntry(), Tool: "diff", User: "first"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	record("second")
+	record("third")
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("transcripts.json still there after the move: %v", err)
+	}
+	path := filepath.Join(dir, "transcripts.jsonl")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		var tr Transcript
+		if err := json.Unmarshal([]byte(line), &tr); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		users = append(users, tr.User)
+	}
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(users, want) {
+		t.Errorf("logged prompts = %q, want %q", users, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, %v; want 600", info.Mode().Perm(), err)
+	}
+}
+
+func TestRecordTranscriptDisabled(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv(transcriptEnv, "")
+	record("question")
+	s, err := openTranscripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items, err := s.Load(); err != nil || len(items) != 0 {
+		t.Errorf("Load = %d transcripts, %v; want none logged", len(items), err)
+	}
+}
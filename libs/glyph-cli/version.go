@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"io"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// VersionCommand returns a "version" subcommand that prints detailed build
+// information for the binary, rendered with the active theme. version is
+// the ldflags-injected Version string of the calling tool.
+func VersionCommand(version string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print detailed build information",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			WriteBuildInfo(version, cmd.OutOrStdout())
+			return nil
+		},
+	}
+}
+
+// WriteBuildInfo writes the build information table the version
+// subcommand prints to w. It is exported for ask, whose free-form
+// question leaves no room for a version subcommand.
+func WriteBuildInfo(version string, w io.Writer) {
+	info := core.ReadBuildInfo(version)
+	theme := ink.ThemeFrom(viper.GetString("style"))
+
+	commit := info.ShortCommit()
+	if commit == "" {
+		commit = "unknown"
+	} else if info.Modified {
+		commit += " (dirty)"
+	}
+	date := info.Date
+	if date == "" {
+		date = "unknown"
+	}
+
+	tbl := theme.Table().Headers("FIELD", "VALUE")
+	tbl.Row("version", info.Version)
+	tbl.Row("commit", commit)
+	tbl.Row("built", date)
+	tbl.Row("go", info.GoVersion)
+	tbl.Row("platform", info.Platform)
+	tbl.Render(w)
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// BuildInfo describes the exact build of a glyph binary.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	Date      string
+	Modified  bool
+	GoVersion string
+	Platform  string
+}
+
+// ReadBuildInfo returns BuildInfo for the running binary. VCS data comes from
+// runtime/debug.ReadBuildInfo; version is the ldflags-injected string and is
+// used as-is, falling back to the module version when it is still "dev".
+func ReadBuildInfo(version string) BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if (info.Version == "" || info.Version == "dev") &&
+		bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+		case "vcs.time":
+			if t, err := time.Parse(time.RFC3339, s.Value); err == nil {
+				info.Date = t.UTC().Format(time.DateTime)
+			} else {
+				info.Date = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+// ShortCommit returns the first 12 characters of the commit hash.
+func (b BuildInfo) ShortCommit() string {
+	if len(b.Commit) <= 12 {
+		return b.Commit
+	}
+	return b.Commit[:12]
+}
@@ -1,9 +1,14 @@
 package core
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -15,20 +20,174 @@ type Config struct {
 	APIKey       string `toml:"api_key"`
 	OllamaHost   string `toml:"ollama_host"`
 	DefaultStyle string `toml:"default_style"`
+
+	// APIKeyCommand is a shell command that prints the API key, such as
+	// "pass show groq/key", so the key need not be stored in the file. It
+	// takes precedence over APIKey.
+	APIKeyCommand string `toml:"api_key_command"`
+
+	// APIKeys holds the keys of other providers by name, such as
+	// api_keys.claude, for fallbacks: api_key only ever goes to
+	// ai_provider, so a fallback to another keyed provider needs its own.
+	APIKeys map[string]string `toml:"api_keys"`
+	// APIKeyCommands is api_key_command for the providers of APIKeys, and
+	// takes precedence over them.
+	APIKeyCommands map[string]string `toml:"api_key_commands"`
+
+	// HTTPProxy routes provider requests through this proxy URL instead of
+	// the one from HTTP_PROXY/HTTPS_PROXY/NO_PROXY.
+	HTTPProxy string `toml:"http_proxy"`
+	// CABundle is a PEM file of extra CA certificates to trust, for proxies
+	// that intercept TLS.
+	CABundle string `toml:"ca_bundle"`
+	// InsecureSkipVerify disables TLS certificate checks. For testing only.
+	InsecureSkipVerify bool `toml:"insecure_skip_verify"`
+
+	Theme ThemeConfig `toml:"theme"`
+
+	// Azure locates the deployment used by the azure provider.
+	Azure AzureConfig `toml:"azure"`
+
+	// Pin holds options of the pin tool.
+	Pin PinConfig `toml:"pin"`
+
+	// Ask holds options of the ask tool.
+	Ask AskConfig `toml:"ask"`
+
+	// Models maps a provider name to the model used when ai_model is empty
+	// or names another provider's model, e.g. models.ollama = "qwen2.5-coder".
+	Models map[string]string `toml:"models"`
+
+	// Fallbacks lists "provider:model" pairs to try in order when the
+	// configured provider fails to start a response. The model may be
+	// omitted to use the provider's default.
+	Fallbacks []string `toml:"fallbacks"`
+
+	// SystemRole is the role OpenAI-style APIs receive the instructions
+	// under: "system" (the default) or "developer", which newer OpenAI
+	// models prefer.
+	SystemRole string `toml:"system_role"`
+
+	// Stop lists stop sequences: the model stops generating when it would
+	// produce one of them, and the sequence itself is not returned.
+	Stop []string `toml:"stop"`
+
+	// Seed asks the provider to sample deterministically, so that the same
+	// request with the same seed gets the same answer as far as the
+	// provider allows. Nil leaves sampling random. Claude has no seed.
+	Seed *int64 `toml:"seed"`
+
+	// ResponseFormat is "text" (the default) or "json", which asks the
+	// provider for a single JSON object and fails answers that are not one.
+	ResponseFormat string `toml:"response_format"`
+	// JSONSchema is the path of a JSON schema file the answer must follow.
+	// It implies response_format = "json".
+	JSONSchema string `toml:"json_schema"`
+
+	// Retries is how many times diff and stand retry a request that fails
+	// before its first chunk. 0 disables retrying.
+	Retries int `toml:"retries"`
+
+	// MaxInputBytes caps how much piped input is sent with a question;
+	// longer input is truncated with a warning. 0 disables the limit.
+	MaxInputBytes int64 `toml:"max_input_bytes"`
+
+	// MaxRequestBytes caps the size of a request body sent to a provider,
+	// as a last guard against sending an enormous prompt. 0 disables it.
+	MaxRequestBytes int64 `toml:"max_request_bytes"`
+
+	// extras holds keys found in the config file that Config does not know
+	// about, so that WriteConfig can write them back instead of dropping them.
+	extras map[string]any
+}
+
+// ThemeConfig holds presentation options shared by every theme.
+type ThemeConfig struct {
+	// Symbols selects Success/Error prefixes: "text" (or "ascii") forces
+	// "[OK]"/"[ERR]" so outcomes never rely on color alone.
+	Symbols string `toml:"symbols"`
+	// Border is the color of rounded table borders, such as "#44475A";
+	// empty means a muted grey.
+	Border string `toml:"border"`
+}
+
+// AzureConfig locates an Azure OpenAI deployment for the azure provider.
+type AzureConfig struct {
+	// Resource is the Azure OpenAI resource name, as in
+	// https://<resource>.openai.azure.com.
+	Resource string `toml:"resource"`
+	// Endpoint is the resource URL, for custom domains. It takes the place
+	// of Resource.
+	Endpoint string `toml:"endpoint"`
+	// Deployment is the deployment name, which selects the model.
+	Deployment string `toml:"deployment"`
+	// APIVersion is the api-version of the REST API; empty means a recent
+	// stable version.
+	APIVersion string `toml:"api_version"`
+}
+
+// PinConfig holds options of the pin tool, under [pin].
+type PinConfig struct {
+	// DefaultTag is the tag pin add gives entries when --tag is not
+	// passed. --tag "" still adds an entry without a tag.
+	DefaultTag string `toml:"default_tag"`
+}
+
+// AskConfig holds options of the ask tool, under [ask].
+type AskConfig struct {
+	// HistoryTurns is how many of the latest exchanges asked with
+	// --continue ask keeps for the next one to build on. 0 keeps none.
+	HistoryTurns int `toml:"history_turns"`
+	// HistoryMaxChars caps the characters of those exchanges, counted from
+	// the newest, both kept and sent with --continue. 0 disables the cap.
+	HistoryMaxChars int `toml:"history_max_chars"`
 }
 
+// DefaultHistoryTurns is the default for AskConfig.HistoryTurns.
+const DefaultHistoryTurns = 5
+
+// DefaultHistoryMaxChars is the default for AskConfig.HistoryMaxChars.
+const DefaultHistoryMaxChars = 20_000
+
+// DefaultMaxInputBytes is the default for Config.MaxInputBytes.
+const DefaultMaxInputBytes = 256_000
+
+// DefaultMaxRequestBytes is the default for Config.MaxRequestBytes.
+const DefaultMaxRequestBytes = 4_000_000
+
 // DefaultConfig returns a Config populated with sensible defaults.
 func DefaultConfig() Config {
 	return Config{
 		AIProvider:   "groq",
-		AIModel:      "llama-3.3-70b-versatile",
 		OllamaHost:   "http://localhost:11434",
-		DefaultStyle: "rounded",
+		DefaultStyle: "auto",
+
+		MaxInputBytes:   DefaultMaxInputBytes,
+		MaxRequestBytes: DefaultMaxRequestBytes,
+		Retries:         2,
+
+		Ask: AskConfig{
+			HistoryTurns:    DefaultHistoryTurns,
+			HistoryMaxChars: DefaultHistoryMaxChars,
+		},
 	}
 }
 
-// configPath returns the path to the config file.
-func configPath() (string, error) {
+// configOverride replaces the default config path when set.
+var configOverride string
+
+// SetConfigPath makes LoadConfig and WriteConfig use path instead of
+// ~/.config/glyph/config.toml. An empty path restores the default.
+func SetConfigPath(path string) {
+	configOverride = path
+}
+
+// ConfigPath returns the path of the config file LoadConfig reads, which
+// need not exist.
+func ConfigPath() (string, error) {
+	if configOverride != "" {
+		return configOverride, nil
+	}
 	cfgDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("cannot locate config dir: %w", err)
@@ -36,33 +195,141 @@ func configPath() (string, error) {
 	return filepath.Join(cfgDir, "glyph", "config.toml"), nil
 }
 
-// LoadConfig reads config from ~/.config/glyph/config.toml.
-// If the file does not exist, defaults are returned without error.
+// LoadConfig reads config from ~/.config/glyph/config.toml, or the path
+// given to SetConfigPath. If the default file does not exist, defaults are
+// returned without error.
 func LoadConfig() (Config, error) {
 	cfg := DefaultConfig()
 
-	path, err := configPath()
+	path, err := ConfigPath()
 	if err != nil {
 		return cfg, err
 	}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if configOverride != "" {
+			// A file named explicitly must exist.
+			return cfg, &AppError{Msg: "config file not found: " + path}
+		}
 		return cfg, nil
 	}
 
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, &AppError{
+			Msg: "cannot read config file",
+			Err: err,
+		}
+	}
+	text := NormalizeText(string(data))
+
+	md, err := toml.Decode(text, &cfg)
+	if err != nil {
 		return cfg, &AppError{
 			Msg: "failed to parse config file",
 			Err: err,
 		}
 	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var raw map[string]any
+		if _, err := toml.Decode(text, &raw); err != nil {
+			return cfg, &AppError{
+				Msg: "failed to parse config file",
+				Err: err,
+			}
+		}
+		cfg.extras = collectExtras(raw, undecoded)
+	}
 	return cfg, nil
 }
 
+// collectExtras copies the values at the given undecoded keys out of raw,
+// preserving their table nesting. Keys nested under an already-copied table
+// are skipped since the table value carries them.
+func collectExtras(raw map[string]any, keys []toml.Key) map[string]any {
+	extras := map[string]any{}
+	copied := map[string]bool{}
+	for _, key := range keys {
+		if underCopied(copied, key) {
+			continue
+		}
+		v, ok := lookupKey(raw, key)
+		if !ok {
+			continue
+		}
+		setKey(extras, key, v)
+		copied[key.String()] = true
+	}
+	return extras
+}
+
+// underCopied reports whether a proper prefix of key has already been copied.
+func underCopied(copied map[string]bool, key toml.Key) bool {
+	for i := 1; i < len(key); i++ {
+		if copied[key[:i].String()] {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupKey returns the value at key in m, descending through tables.
+func lookupKey(m map[string]any, key toml.Key) (any, bool) {
+	var cur any = m
+	for _, k := range key {
+		tbl, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = tbl[k]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// setKey stores v at key in m, creating intermediate tables as needed.
+func setKey(m map[string]any, key toml.Key, v any) {
+	for _, k := range key[:len(key)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[key[len(key)-1]] = v
+}
+
+// mergeExtras adds extras into dst without overwriting keys dst already has.
+func mergeExtras(dst, extras map[string]any) {
+	for k, v := range extras {
+		existing, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dstTbl, ok1 := existing.(map[string]any)
+		extraTbl, ok2 := v.(map[string]any)
+		if ok1 && ok2 {
+			mergeExtras(dstTbl, extraTbl)
+		}
+	}
+}
+
+// configBackups is how many timestamped backups of the config file
+// WriteConfig keeps.
+const configBackups = 5
+
 // WriteConfig persists cfg to ~/.config/glyph/config.toml,
-// creating the directory if needed.
+// creating the directory if needed. The file is replaced atomically, so a
+// failed write leaves the previous config intact, and the previous
+// contents are first copied to config.toml.<timestamp>.bak; the newest
+// configBackups backups are kept. The file keeps its permissions, and a
+// symlinked config file is written through rather than replaced.
 func WriteConfig(cfg Config) error {
-	path, err := configPath()
+	path, err := ConfigPath()
 	if err != nil {
 		return err
 	}
@@ -73,22 +340,134 @@ func WriteConfig(cfg Config) error {
 			Err: err,
 		}
 	}
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
 
-	f, err := os.Create(path)
+	data, err := encodeConfig(cfg)
 	if err != nil {
 		return &AppError{
-			Msg: "cannot write config file",
+			Msg: "cannot encode config",
 			Err: err,
 		}
 	}
-	defer f.Close()
 
-	enc := toml.NewEncoder(f)
-	if err := enc.Encode(cfg); err != nil {
+	perm := os.FileMode(0o644)
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if bytes.Equal(old, data) {
+			return nil
+		}
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+		}
+		if err := backupConfig(path, old, perm); err != nil {
+			return &AppError{
+				Msg: "cannot back up config file",
+				Err: err,
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
 		return &AppError{
-			Msg: "cannot encode config",
+			Msg: "cannot read config file",
+			Err: err,
+		}
+	}
+
+	if err := writeFileAtomic(path, data, perm); err != nil {
+		return &AppError{
+			Msg: "cannot write config file",
 			Err: err,
 		}
 	}
 	return nil
 }
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path, like the store does, so readers see either the old or the
+// new contents.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	// WriteFile only applies perm to new files; enforce it on a leftover
+	// temp file too.
+	if err := os.Chmod(tmp, perm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// backupConfig writes data, the contents of the config file at path, to a
+// timestamped backup beside it, and removes all but the newest
+// configBackups backups. A backup made earlier in the same second is
+// kept, as it holds the older config.
+func backupConfig(path string, data []byte, perm os.FileMode) error {
+	name := path + "." + time.Now().Format("20060102-150405") + ".bak"
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	switch {
+	case errors.Is(err, fs.ErrExist):
+		return nil
+	case err != nil:
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(name)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(name)
+		return err
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil
+	}
+	// ReadDir sorts by name, and the timestamps sort in time order.
+	prefix := filepath.Base(path) + "."
+	var backups []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) && strings.HasSuffix(e.Name(), ".bak") {
+			backups = append(backups, filepath.Join(filepath.Dir(path), e.Name()))
+		}
+	}
+	for len(backups) > configBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// encodeConfig renders cfg as TOML, re-emitting any unknown keys that were
+// read by LoadConfig alongside the known fields.
+func encodeConfig(cfg Config) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return nil, err
+	}
+	if len(cfg.extras) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	var merged map[string]any
+	if _, err := toml.Decode(buf.String(), &merged); err != nil {
+		return nil, err
+	}
+	mergeExtras(merged, cfg.extras)
+
+	buf.Reset()
+	if err := toml.NewEncoder(&buf).Encode(merged); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
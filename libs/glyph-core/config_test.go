@@ -0,0 +1,227 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+// useConfig points LoadConfig and WriteConfig at path for the test.
+func useConfig(t *testing.T, path string) {
+	t.Helper()
+	SetConfigPath(path)
+	t.Cleanup(func() { SetConfigPath("") })
+}
+
+func TestLoadConfigBOMAndCRLF(t *testing.T) {
+	useConfig(t, "testdata/bom_crlf.toml")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AIProvider != "ollama" {
+		t.Errorf("ai_provider = %q, want ollama", cfg.AIProvider)
+	}
+	if want := []string{"END\n"}; !reflect.DeepEqual(cfg.Stop, want) {
+		t.Errorf("stop = %q, want %q", cfg.Stop, want)
+	}
+	want := map[string]any{"notes": map[string]any{"body": "line1\nline2"}}
+	if !reflect.DeepEqual(cfg.extras, want) {
+		t.Errorf("extras = %#v, want %#v", cfg.extras, want)
+	}
+}
+
+// backups returns the names of the config backups beside path, oldest
+// first.
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	names, err := filepath.Glob(path + ".*.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range names {
+		names[i] = filepath.Base(name)
+	}
+	return names
+}
+
+func TestWriteConfigBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	useConfig(t, path)
+	old := "ai_provider = \"ollama\"\n"
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 6 {
+		name := fmt.Sprintf("%s.20200101-00000%d.bak", path, i)
+		if err := os.WriteFile(name, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := WriteConfig(Config{AIProvider: "groq"}); err != nil {
+		t.Fatal(err)
+	}
+	got := backups(t, path)
+	if len(got) != configBackups {
+		t.Fatalf("backups = %q, want %d", got, configBackups)
+	}
+	if got[0] != "config.toml.20200101-000002.bak" {
+		t.Errorf("oldest kept backup = %s, want the two oldest removed", got[0])
+	}
+	newest, err := os.ReadFile(filepath.Join(filepath.Dir(path), got[len(got)-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(newest) != old {
+		t.Errorf("newest backup = %q, want the previous config %q", newest, old)
+	}
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestWriteConfigUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	useConfig(t, path)
+	cfg := Config{AIProvider: "groq", AIModel: "llama-3.3-70b-versatile"}
+	if err := WriteConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := backups(t, path); len(got) != 0 {
+		t.Errorf("first write made backups %q", got)
+	}
+	if err := WriteConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := backups(t, path); len(got) != 0 {
+		t.Errorf("writing the same config made backups %q", got)
+	}
+}
+
+func TestWriteConfigKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	useConfig(t, path)
+	if err := os.WriteFile(path, []byte("api_key = \"secret\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteConfig(Config{APIKey: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range append(backups(t, path), filepath.Base(path)) {
+		info, err := os.Stat(filepath.Join(filepath.Dir(path), name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("%s mode = %o, want 600", name, perm)
+		}
+	}
+}
+
+func TestWriteConfigSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("ai_provider = \"ollama\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "config.toml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	useConfig(t, link)
+
+	if err := WriteConfig(Config{AIProvider: "claude"}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("config symlink was replaced by a %v", info.Mode())
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AIProvider != "claude" {
+		t.Errorf("ai_provider through the link = %q, want claude", cfg.AIProvider)
+	}
+	if got := backups(t, target); len(got) != 1 {
+		t.Errorf("backups beside the target = %q, want 1", got)
+	}
+}
+
+// decodeRaw decodes the TOML file at path into plain maps.
+func decodeRaw(t *testing.T, path string) map[string]any {
+	t.Helper()
+	var raw map[string]any
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestWriteConfigKeepsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	data, err := os.ReadFile("testdata/unknown_keys.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	useConfig(t, path)
+	before := decodeRaw(t, path)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.AIModel = "llama-3.1-8b-instant"
+	cfg.Pin.DefaultTag = "work"
+	if err := WriteConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	after := decodeRaw(t, path)
+	for _, key := range []string{"telemetry", "aliases", "plugins", "hooks"} {
+		if !reflect.DeepEqual(after[key], before[key]) {
+			t.Errorf("%s = %#v, want %#v", key, after[key], before[key])
+		}
+	}
+	theme := after["theme"].(map[string]any)
+	if theme["accent"] != "#ff79c6" || theme["symbols"] != "text" {
+		t.Errorf("theme = %#v, want accent kept beside symbols", theme)
+	}
+	pin := after["pin"].(map[string]any)
+	if pin["default_tag"] != "work" || !reflect.DeepEqual(pin["sync"], before["pin"].(map[string]any)["sync"]) {
+		t.Errorf("pin = %#v, want the new default_tag and sync kept", pin)
+	}
+	if after["ai_model"] != "llama-3.1-8b-instant" {
+		t.Errorf("ai_model = %v, want the edited value", after["ai_model"])
+	}
+
+	// A second round trip changes nothing.
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, _ := os.ReadFile(path)
+	if err := WriteConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(written) {
+		t.Errorf("second round trip rewrote the file:\n%s\nwas\n%s", again, written)
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sinceLayouts are the absolute date formats ParseSince accepts. Values
+// without a zone are read in local time.
+var sinceLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseSince parses a point in the past relative to now:
+//
+//   - "today" and "yesterday": local midnight of that day
+//   - "3 days ago", "1 week", "2 hours ago": a count of minutes, hours,
+//     days, weeks, months or years, with or without "ago"
+//   - "90d", "2w": a count of days or weeks, or any time.ParseDuration
+//     value such as "12h"
+//   - "2026-01-31", "2026-01-31 09:00" or RFC 3339: that date and time
+func ParseSince(s string) (time.Time, error) {
+	return parseSince(s, time.Now())
+}
+
+func parseSince(s string, now time.Time) (time.Time, error) {
+	v := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch v {
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	if t, ok := parseRelative(strings.TrimSuffix(v, " ago"), now, -1); ok {
+		return t, nil
+	}
+	if t, ok := parseDate(s, now); ok {
+		return t, nil
+	}
+	return time.Time{}, &AppError{Msg: fmt.Sprintf(
+		"invalid date %q: use today, yesterday, a count such as \"3 days ago\", 2w or 12h, or a date such as 2026-01-31", s)}
+}
+
+// ParseUntil parses a point in the future relative to now, such as an
+// expiry: a count as in ParseSince but counted forward ("7 days", "1w",
+// "12h", optionally prefixed with "in"), or a date such as 2026-01-31.
+func ParseUntil(s string) (time.Time, error) {
+	return parseUntil(s, time.Now())
+}
+
+func parseUntil(s string, now time.Time) (time.Time, error) {
+	v := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if t, ok := parseRelative(strings.TrimPrefix(v, "in "), now, 1); ok {
+		return t, nil
+	}
+	if t, ok := parseDate(s, now); ok {
+		return t, nil
+	}
+	return time.Time{}, &AppError{Msg: fmt.Sprintf(
+		"invalid duration %q: use a count such as 7d, 2w, 12h or \"3 months\", or a date such as 2026-01-31", s)}
+}
+
+// parseDate parses s in one of sinceLayouts, in now's location.
+func parseDate(s string, now time.Time) (time.Time, bool) {
+	for _, layout := range sinceLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), now.Location()); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseRelative parses "<n> <unit>[s]", "<n>d", "<n>w" and Go durations,
+// counting from now back (dir -1) or forward (dir 1).
+func parseRelative(v string, now time.Time, dir int) (time.Time, bool) {
+	if n, unit, ok := strings.Cut(v, " "); ok {
+		count, err := strconv.Atoi(n)
+		if err != nil || count < 0 {
+			return time.Time{}, false
+		}
+		switch strings.TrimSuffix(unit, "s") {
+		case "minute", "min":
+			return now.Add(time.Duration(dir*count) * time.Minute), true
+		case "hour":
+			return now.Add(time.Duration(dir*count) * time.Hour), true
+		case "day":
+			return now.AddDate(0, 0, dir*count), true
+		case "week":
+			return now.AddDate(0, 0, dir*7*count), true
+		case "month":
+			return now.AddDate(0, dir*count, 0), true
+		case "year":
+			return now.AddDate(dir*count, 0, 0), true
+		}
+		return time.Time{}, false
+	}
+
+	if n := len(v); n > 1 && (v[n-1] == 'd' || v[n-1] == 'w') {
+		if count, err := strconv.Atoi(v[:n-1]); err == nil && count >= 0 {
+			if v[n-1] == 'w' {
+				count *= 7
+			}
+			return now.AddDate(0, 0, dir*count), true
+		}
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return now.Add(time.Duration(dir) * d), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+// sinceZone is a fixed zone away from UTC, so the tests catch dates read
+// in the wrong location.
+var sinceZone = time.FixedZone("UTC-5", -5*60*60)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 14, 30, 0, 0, sinceZone)
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, sinceZone)
+	}
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"today", at(2026, 3, 15, 0, 0)},
+		{" Today ", at(2026, 3, 15, 0, 0)},
+		{"yesterday", at(2026, 3, 14, 0, 0)},
+		{"3 days ago", at(2026, 3, 12, 14, 30)},
+		{"3  DAYS   ago", at(2026, 3, 12, 14, 30)},
+		{"1 day", at(2026, 3, 14, 14, 30)},
+		{"2 hours ago", at(2026, 3, 15, 12, 30)},
+		{"45 minutes ago", at(2026, 3, 15, 13, 45)},
+		{"10 mins", at(2026, 3, 15, 14, 20)},
+		{"1 week ago", at(2026, 3, 8, 14, 30)},
+		{"2 months ago", at(2026, 1, 15, 14, 30)},
+		{"1 year", at(2025, 3, 15, 14, 30)},
+		{"0 days", now},
+		{"90d", at(2025, 12, 15, 14, 30)},
+		{"2w", at(2026, 3, 1, 14, 30)},
+		{"12h", at(2026, 3, 15, 2, 30)},
+		{"1h30m", at(2026, 3, 15, 13, 0)},
+		{"2026-01-31", at(2026, 1, 31, 0, 0)},
+		{"2026-01-31 09:00", at(2026, 1, 31, 9, 0)},
+		{"2026-01-31 09:00:15", at(2026, 1, 31, 9, 0).Add(15 * time.Second)},
+		{"2026-01-31T09:00:15", at(2026, 1, 31, 9, 0).Add(15 * time.Second)},
+		{"2026-01-31T09:00:00Z", time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if err != nil {
+			t.Errorf("parseSince(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSinceInvalid(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 14, 30, 0, 0, sinceZone)
+	for _, in := range []string{"", "tomorrow", "-3 days", "3 fortnights", "d", "-2w", "-1h", "three days ago", "2026-02-30", "last monday"} {
+		if got, err := parseSince(in, now); err == nil {
+			t.Errorf("parseSince(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 14, 30, 0, 0, sinceZone)
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, sinceZone)
+	}
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", at(2026, 3, 22, 14, 30)},
+		{"in 7d", at(2026, 3, 22, 14, 30)},
+		{"1w", at(2026, 3, 22, 14, 30)},
+		{"12h", at(2026, 3, 16, 2, 30)},
+		{"90m", at(2026, 3, 15, 16, 0)},
+		{"3 days", at(2026, 3, 18, 14, 30)},
+		{"In 2 Weeks", at(2026, 3, 29, 14, 30)},
+		{"30 minutes", at(2026, 3, 15, 15, 0)},
+		{"3 months", at(2026, 6, 15, 14, 30)},
+		{"1 year", at(2027, 3, 15, 14, 30)},
+		{"0d", now},
+		{"2026-12-31", at(2026, 12, 31, 0, 0)},
+		{"2026-12-31 18:00", at(2026, 12, 31, 18, 0)},
+	}
+	for _, tt := range tests {
+		got, err := parseUntil(tt.in, now)
+		if err != nil {
+			t.Errorf("parseUntil(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseUntil(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseUntilInvalid(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 14, 30, 0, 0, sinceZone)
+	// "ago", today and yesterday point back; they are ParseSince values.
+	for _, in := range []string{"", "today", "yesterday", "3 days ago", "-7d", "-1h", "in", "soon"} {
+		if got, err := parseUntil(in, now); err == nil {
+			t.Errorf("parseUntil(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseRelativeDirection(t *testing.T) {
+	now := time.Date(2026, time.January, 31, 12, 0, 0, 0, sinceZone)
+	for _, in := range []string{"5 minutes", "2 hours", "3 days", "1 week", "10d", "2w", "36h", "1 month", "1 year"} {
+		back, ok1 := parseRelative(in, now, -1)
+		forward, ok2 := parseRelative(in, now, 1)
+		if !ok1 || !ok2 {
+			t.Errorf("parseRelative(%q) failed", in)
+			continue
+		}
+		if !back.Before(now) || !forward.After(now) {
+			t.Errorf("parseRelative(%q): back %v, forward %v, want them on either side of %v", in, back, forward, now)
+		}
+	}
+	// Months follow AddDate, which normalizes the 31st of a short month.
+	if got, _ := parseRelative("1 month", now, 1); !got.Equal(time.Date(2026, time.March, 3, 12, 0, 0, 0, sinceZone)) {
+		t.Errorf("1 month after Jan 31 = %v, want Mar 3", got)
+	}
+}
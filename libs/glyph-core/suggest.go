@@ -0,0 +1,46 @@
+package core
+
+import "strings"
+
+// Suggest returns the option closest to s, compared without case, when s
+// looks like a typo of it: at most two edits away and fewer edits than s
+// has characters, so "go" is not taken for "js". It returns false when s
+// is one of options or nothing is close.
+func Suggest(s string, options []string) (string, bool) {
+	best, bestDist := "", 3
+	for _, opt := range options {
+		if opt == s {
+			return "", false
+		}
+		if d := EditDistance(strings.ToLower(s), strings.ToLower(opt)); d < bestDist {
+			best, bestDist = opt, d
+		}
+	}
+	if best == "" || bestDist >= len([]rune(s)) {
+		return "", false
+	}
+	return best, true
+}
+
+// EditDistance returns the Levenshtein distance between a and b: the number
+// of single-rune insertions, deletions and substitutions that turn a into b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,12 @@
+package core
+
+import "strings"
+
+// NormalizeText returns s without a leading UTF-8 byte order mark and with
+// CRLF line endings turned into LF, as Windows editors, tools and
+// PowerShell pipes produce them, so the text holds no stray \r. It is
+// applied to the config file and to piped input.
+func NormalizeText(s string) string {
+	s = strings.TrimPrefix(s, "\uFEFF")
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
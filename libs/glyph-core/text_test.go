@@ -0,0 +1,24 @@
+package core
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "line one\nline two\n", "line one\nline two\n"},
+		{"crlf", "line one\r\nline two\r\n", "line one\nline two\n"},
+		{"bom", "\uFEFFline one\n", "line one\n"},
+		{"bom and crlf", "\uFEFFline one\r\nline two\r\n", "line one\nline two\n"},
+		{"lone cr kept", "a\rb\n", "a\rb\n"},
+		{"bom only at start", "a\uFEFFb", "a\uFEFFb"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeText(tt.in); got != tt.want {
+				t.Errorf("NormalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
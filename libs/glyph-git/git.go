@@ -0,0 +1,110 @@
+// Package git runs the git commands the glyph tools read repositories
+// with. Every function takes the directory to run in; "" means the current
+// directory. Failures are reported as *Error, which carries what git
+// printed, and errors.Is(err, ErrNotRepo) tells a directory outside any
+// repository from other failures. Tools call RequireRepo first to fail
+// with a clear message before running anything else.
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotRepo is returned by RequireRepo, and matches with errors.Is an
+// *Error from running git outside a repository.
+var ErrNotRepo = errors.New("not inside a git repository")
+
+// Error reports a git command that failed.
+type Error struct {
+	Args   []string // the arguments git was run with
+	Stderr string   // what git printed on stderr, trimmed
+	Err    error    // the exec error, such as an *exec.ExitError
+}
+
+// Error returns what git printed, or the exec error when it printed
+// nothing, such as when git is not installed.
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return e.Stderr
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrNotRepo and git complained about not
+// being in a repository.
+func (e *Error) Is(target error) bool {
+	return target == ErrNotRepo && strings.Contains(e.Stderr, "not a git repository")
+}
+
+// Run runs git with args in dir and returns its stdout. git runs in the C
+// locale, so messages such as "not a git repository" can be matched
+// whatever language the user's git speaks.
+func Run(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	var out, errBuf bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		return nil, &Error{Args: args, Stderr: strings.TrimSpace(errBuf.String()), Err: err}
+	}
+	return out.Bytes(), nil
+}
+
+// Log runs git log with args, such as a format and a range.
+func Log(dir string, args ...string) ([]byte, error) {
+	return Run(dir, append([]string{"log"}, args...)...)
+}
+
+// Diff runs git diff with args, such as --cached or a revision.
+func Diff(dir string, args ...string) ([]byte, error) {
+	return Run(dir, append([]string{"diff"}, args...)...)
+}
+
+// Show runs git show with args, such as a commit.
+func Show(dir string, args ...string) ([]byte, error) {
+	return Run(dir, append([]string{"show"}, args...)...)
+}
+
+// Branch returns the name of the checked-out branch, or "HEAD" when HEAD
+// is detached.
+func Branch(dir string) (string, error) {
+	out, err := Run(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	return strings.TrimSpace(string(out)), err
+}
+
+// RepoRoot returns the top-level directory of the working tree dir is in.
+func RepoRoot(dir string) (string, error) {
+	out, err := Run(dir, "rev-parse", "--show-toplevel")
+	return strings.TrimSpace(string(out)), err
+}
+
+// IsRepo reports whether dir is inside a git working tree.
+func IsRepo(dir string) bool {
+	return RequireRepo(dir) == nil
+}
+
+// RequireRepo returns ErrNotRepo unless dir is inside a git working tree;
+// the .git directory itself does not count. Other failures, such as git
+// not being installed, are returned as they are.
+func RequireRepo(dir string) error {
+	out, err := Run(dir, "rev-parse", "--is-inside-work-tree")
+	switch {
+	case errors.Is(err, ErrNotRepo):
+		return ErrNotRepo
+	case err != nil:
+		return err
+	case strings.TrimSpace(string(out)) != "true":
+		return ErrNotRepo
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// repo creates a repository with the commits "first" and "second" on
+// main and returns its directory.
+func repo(t *testing.T) string {
+	t.Helper()
+	isolate(t)
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "first"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "second"},
+	} {
+		if _, err := Run(dir, args...); err != nil {
+			t.Fatalf("git %q: %v", args, err)
+		}
+	}
+	return dir
+}
+
+// isolate keeps the user's git config out of the test and stops git from
+// finding a repository above the temp directories.
+func isolate(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(t.TempDir()))
+}
+
+func TestLog(t *testing.T) {
+	dir := repo(t)
+	out, err := Log(dir, "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "second\nfirst\n"; got != want {
+		t.Errorf("Log = %q, want %q", got, want)
+	}
+}
+
+func TestRepoRoot(t *testing.T) {
+	dir := repo(t)
+	sub := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{dir, sub} {
+		got, err := RepoRoot(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != filepath.ToSlash(want) {
+			t.Errorf("RepoRoot(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestRequireRepo(t *testing.T) {
+	dir := repo(t)
+	outside := t.TempDir()
+	tests := []struct {
+		dir  string
+		want error
+	}{
+		{dir, nil},
+		{filepath.Join(dir, ".git"), ErrNotRepo},
+		{outside, ErrNotRepo},
+	}
+	for _, tt := range tests {
+		if err := RequireRepo(tt.dir); !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+			t.Errorf("RequireRepo(%s) = %v, want %v", tt.dir, err, tt.want)
+		}
+		if got := IsRepo(tt.dir); got != (tt.want == nil) {
+			t.Errorf("IsRepo(%s) = %v", tt.dir, got)
+		}
+	}
+}
+
+func TestNotRepoLocalized(t *testing.T) {
+	isolate(t)
+	// A translated git would not say "not a git repository" here.
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	t.Setenv("LANGUAGE", "de")
+	_, err := Log(t.TempDir())
+	if !errors.Is(err, ErrNotRepo) {
+		t.Errorf("Log outside a repository: err = %v, want it to match ErrNotRepo", err)
+	}
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || gitErr.Args[0] != "log" {
+		t.Errorf("err = %#v, want an *Error for git log", err)
+	}
+}
+
+func TestBranch(t *testing.T) {
+	dir := repo(t)
+	if got, err := Branch(dir); err != nil || got != "main" {
+		t.Errorf("Branch = %q, %v; want main", got, err)
+	}
+	if _, err := Run(dir, "checkout", "-q", "--detach"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Branch(dir); err != nil || got != "HEAD" {
+		t.Errorf("Branch with HEAD detached = %q, %v; want HEAD", got, err)
+	}
+}
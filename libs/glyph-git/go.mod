@@ -0,0 +1,3 @@
+module github.com/reky0/glyph-git
+
+go 1.24
@@ -0,0 +1,72 @@
+package ink
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// autoTheme is the style name that picks a theme from the terminal.
+const autoTheme = "auto"
+
+// detectTheme returns the theme "auto" resolves to: rounded when stdout is
+// a terminal that can draw box characters, ascii otherwise. A terminal is
+// considered capable when TERM is not "dumb" and the locale is UTF-8; on
+// Windows, where neither variable is usually set, any console qualifies.
+func detectTheme() Theme {
+	if !stdoutIsTerminal() {
+		return asciiTheme{}
+	}
+	if runtime.GOOS == "windows" && os.Getenv("TERM") == "" {
+		return roundedTheme{}
+	}
+	if term := os.Getenv("TERM"); term == "" || term == "dumb" {
+		return asciiTheme{}
+	}
+	if !utf8Locale() {
+		return asciiTheme{}
+	}
+	return roundedTheme{}
+}
+
+// stdoutIsTerminal reports whether stdout is a character device.
+func stdoutIsTerminal() bool {
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalWidth returns the width of the terminal stdout is attached to, in
+// cells, or 0 when stdout is not a terminal or its size is unknown.
+func TerminalWidth() int {
+	if !stdoutIsTerminal() {
+		return 0
+	}
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// utf8Locale reports whether the effective locale uses UTF-8, following the
+// POSIX precedence of LC_ALL over LC_CTYPE over LANG.
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
+
+// DetectedTheme returns the name of the theme "auto" resolves to in the
+// current terminal.
+func DetectedTheme() string {
+	if _, ok := detectTheme().(roundedTheme); ok {
+		return "rounded"
+	}
+	return "ascii"
+}
@@ -0,0 +1,54 @@
+package ink
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// ErrNotTerminal is returned by Confirm when stdin is not a terminal, so
+// there is nobody to answer the prompt.
+var ErrNotTerminal = errors.New("cannot ask for confirmation: stdin is not a terminal")
+
+// Confirm asks a yes/no question on stderr, with the [y/N] hint in theme's
+// muted style, and reads the answer from stdin.
+// "y", "yes", "n" and "no" are accepted in any case, an empty answer picks
+// defaultYes, and anything else asks again. Ctrl-D (EOF) means no.
+//
+// When stdin is not a terminal Confirm does not prompt: it returns
+// defaultYes together with ErrNotTerminal, so scripts never block and the
+// caller decides whether the default is safe to act on.
+func Confirm(theme Theme, prompt string, defaultYes bool) (bool, error) {
+	// A character device is not enough: /dev/null is one too.
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return defaultYes, ErrNotTerminal
+	}
+
+	hint := "[y/N]"
+	if defaultYes {
+		hint = "[Y/n]"
+	}
+	hint = theme.Muted(hint)
+	in := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "%s %s ", prompt, hint)
+		answer, err := in.ReadString('\n')
+		if err != nil {
+			// EOF (Ctrl-D) leaves the cursor on the prompt line.
+			fmt.Fprintln(os.Stderr)
+			return false, nil
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return defaultYes, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
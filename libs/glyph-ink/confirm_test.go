@@ -0,0 +1,34 @@
+package ink
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestConfirmWithoutTerminal(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	w.WriteString("y\n")
+	w.Close()
+
+	stdin := os.Stdin
+	t.Cleanup(func() { os.Stdin = stdin })
+	for name, f := range map[string]*os.File{"pipe": r, "/dev/null": devNull} {
+		os.Stdin = f
+		for _, defaultYes := range []bool{false, true} {
+			got, err := Confirm(ThemeFrom("raw"), "go on?", defaultYes)
+			if !errors.Is(err, ErrNotTerminal) || got != defaultYes {
+				t.Errorf("%s: Confirm(default %v) = %v, %v; want the default and ErrNotTerminal", name, defaultYes, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ink
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DiffColors are the lipgloss colors ColorDiff uses for each kind of line
+// of a unified diff. An empty color leaves those lines unstyled.
+type DiffColors struct {
+	Added   string // lines starting with "+"
+	Removed string // lines starting with "-"
+	Hunk    string // "@@ -1,2 +1,3 @@" hunk headers
+	File    string // "diff --git", "index", "---" and "+++" file headers, in bold
+}
+
+// ColorDiff is the default implementation of Theme.RenderDiff: it styles
+// each line of the unified diff by its kind. Lines that are not part of a
+// diff, such as a commit message from git show, are left alone. Themes
+// passed to RegisterTheme can implement RenderDiff by calling it.
+func ColorDiff(diff string, c DiffColors) string {
+	color := func(fg string, bold bool, s string) string {
+		if fg == "" && !bold {
+			return s
+		}
+		style := lipgloss.NewStyle().Bold(bold)
+		if fg != "" {
+			style = style.Foreground(lipgloss.Color(fg))
+		}
+		return style.Render(s)
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	inHunk := false
+	for i, line := range lines {
+		text, nl := strings.CutSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "diff "):
+			inHunk = false
+			text = color(c.File, true, text)
+		case strings.HasPrefix(text, "@@"):
+			inHunk = true
+			text = color(c.Hunk, false, text)
+		case !inHunk && (strings.HasPrefix(text, "--- ") || strings.HasPrefix(text, "+++ ") || strings.HasPrefix(text, "index ")):
+			text = color(c.File, true, text)
+		case inHunk && strings.HasPrefix(text, "+"):
+			text = color(c.Added, false, text)
+		case inHunk && strings.HasPrefix(text, "-"):
+			text = color(c.Removed, false, text)
+		}
+		if nl {
+			text += "\n"
+		}
+		lines[i] = text
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,51 @@
+package ink
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HyperlinksSupported reports whether stdout is a terminal known to render
+// OSC 8 hyperlinks. Detection is conservative: terminals that do not
+// identify themselves are assumed not to, and NO_COLOR disables links.
+func HyperlinksSupported() bool {
+	if os.Getenv("NO_COLOR") != "" || !stdoutIsTerminal() {
+		return false
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	// VTE-based terminals (GNOME Terminal, Tilix, ...) support OSC 8 since
+	// VTE 0.50.
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	switch os.Getenv("TERM") {
+	case "xterm-kitty", "xterm-ghostty", "wezterm", "foot", "alacritty":
+		return true
+	}
+	return false
+}
+
+// link renders text as an OSC 8 hyperlink to url when the terminal supports
+// it, and as "text (url)" otherwise. A text that is already the url is not
+// repeated.
+func link(text, url string) string {
+	if HyperlinksSupported() {
+		return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+	}
+	return plainLink(text, url)
+}
+
+// plainLink is the escape-free form of link.
+func plainLink(text, url string) string {
+	if text == url || strings.TrimSpace(url) == "" {
+		return text
+	}
+	return text + " (" + url + ")"
+}
@@ -0,0 +1,55 @@
+package ink
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// KVRenderer lays out key/value pairs in two aligned columns, for showing
+// one record in detail where a table would need a single, very wide row.
+// Keys are muted; a value spanning several lines continues under its first
+// line.
+type KVRenderer struct {
+	theme  Theme
+	keys   []string
+	values []string
+}
+
+// NewKV returns an empty KVRenderer styled by theme.
+func NewKV(theme Theme) *KVRenderer {
+	return &KVRenderer{theme: theme}
+}
+
+// Add appends a pair. Empty values are kept, so callers decide what to
+// leave out.
+func (kv *KVRenderer) Add(key, value string) *KVRenderer {
+	kv.keys = append(kv.keys, key)
+	kv.values = append(kv.values, value)
+	return kv
+}
+
+// String returns the pairs, one per line, with values aligned two cells
+// past the longest key.
+func (kv *KVRenderer) String() string {
+	width := 0
+	for _, k := range kv.keys {
+		width = max(width, ansi.StringWidth(k))
+	}
+	indent := strings.Repeat(" ", width+2)
+
+	var b strings.Builder
+	for i, k := range kv.keys {
+		b.WriteString(kv.theme.Muted(k))
+		b.WriteString(indent[ansi.StringWidth(k):])
+		lines := strings.Split(strings.TrimRight(kv.values[i], "\n"), "\n")
+		for n, line := range lines {
+			if n > 0 {
+				b.WriteString(indent)
+			}
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,173 @@
+package ink
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI sequences used by the light markdown styler. They are emitted
+// directly because spans are styled as they stream, before their end is
+// known, which rules out lipgloss' whole-string rendering.
+const (
+	ansiBoldOn    = "\x1b[1m"
+	ansiBoldOff   = "\x1b[22m"
+	ansiItalicOn  = "\x1b[3m"
+	ansiItalicOff = "\x1b[23m"
+	ansiCodeOn    = "\x1b[38;5;141m"
+	ansiCodeOff   = "\x1b[39m"
+)
+
+// mdStyler incrementally converts inline **bold**, *italic* and `code`
+// spans to ANSI while text streams in. Block structure (list bullets,
+// fenced code blocks, headings) is passed through untouched. Marker runs
+// that end a chunk are held back until the next chunk shows whether they
+// continue, so markers split across chunks are handled correctly.
+type mdStyler struct {
+	pending   string
+	prev      rune
+	lineStart bool
+	bold      bool
+	italic    bool
+	code      bool
+	fence     bool
+}
+
+func newMDStyler() *mdStyler {
+	return &mdStyler{lineStart: true, prev: '\n'}
+}
+
+// Write styles chunk and returns the text that can be emitted now.
+func (m *mdStyler) Write(chunk string) string {
+	buf := m.pending + chunk
+	m.pending = ""
+
+	var out strings.Builder
+	for i := 0; i < len(buf); {
+		if !utf8.FullRuneInString(buf[i:]) {
+			// A multi-byte rune was split across chunks.
+			m.pending = buf[i:]
+			break
+		}
+		r, size := utf8.DecodeRuneInString(buf[i:])
+
+		if r != '*' && r != '`' {
+			if r == '\n' {
+				m.closeInline(&out)
+			}
+			out.WriteString(buf[i : i+size])
+			m.advance(r)
+			i += size
+			continue
+		}
+
+		run := markerRun(buf[i:], byte(r))
+		if i+run == len(buf) {
+			// The run may continue in the next chunk.
+			m.pending = buf[i:]
+			break
+		}
+		next, _ := utf8.DecodeRuneInString(buf[i+run:])
+		marker := buf[i : i+run]
+		if r == '`' {
+			m.backticks(&out, marker)
+		} else {
+			m.stars(&out, marker, next)
+		}
+		m.advance(r)
+		i += run
+	}
+	return out.String()
+}
+
+// Flush emits any held-back markers literally and closes open styles.
+func (m *mdStyler) Flush() string {
+	var out strings.Builder
+	out.WriteString(m.pending)
+	m.pending = ""
+	m.closeInline(&out)
+	return out.String()
+}
+
+func (m *mdStyler) backticks(out *strings.Builder, marker string) {
+	switch {
+	case len(marker) >= 3 && m.lineStart && !m.code:
+		m.fence = !m.fence
+		out.WriteString(marker)
+	case len(marker) == 1 && !m.fence:
+		if m.code {
+			out.WriteString(ansiCodeOff)
+		} else {
+			out.WriteString(ansiCodeOn)
+		}
+		m.code = !m.code
+	default:
+		out.WriteString(marker)
+	}
+}
+
+func (m *mdStyler) stars(out *strings.Builder, marker string, next rune) {
+	if m.code || m.fence {
+		out.WriteString(marker)
+		return
+	}
+	prevSolid := !unicode.IsSpace(m.prev)
+	nextSolid := !unicode.IsSpace(next)
+
+	switch len(marker) {
+	case 1:
+		switch {
+		case m.italic && prevSolid:
+			out.WriteString(ansiItalicOff)
+			m.italic = false
+		case !m.italic && nextSolid:
+			out.WriteString(ansiItalicOn)
+			m.italic = true
+		default:
+			out.WriteString(marker)
+		}
+	case 2:
+		switch {
+		case m.bold && prevSolid:
+			out.WriteString(ansiBoldOff)
+			m.bold = false
+		case !m.bold && nextSolid:
+			out.WriteString(ansiBoldOn)
+			m.bold = true
+		default:
+			out.WriteString(marker)
+		}
+	default:
+		out.WriteString(marker)
+	}
+}
+
+// closeInline ends any open inline span; spans never cross lines.
+func (m *mdStyler) closeInline(out *strings.Builder) {
+	if m.bold {
+		out.WriteString(ansiBoldOff)
+		m.bold = false
+	}
+	if m.italic {
+		out.WriteString(ansiItalicOff)
+		m.italic = false
+	}
+	if m.code {
+		out.WriteString(ansiCodeOff)
+		m.code = false
+	}
+}
+
+func (m *mdStyler) advance(r rune) {
+	m.prev = r
+	m.lineStart = r == '\n'
+}
+
+// markerRun returns how many consecutive c bytes s starts with.
+func markerRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package ink
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// DefaultPager is the pager Page runs when $PAGER is unset. -R passes
+// colors through instead of showing escape codes.
+const DefaultPager = "less -R"
+
+// Page writes s to stdout, through the user's pager when stdout is a
+// terminal and s is taller than it, so a long table can be scrolled
+// instead of running off the screen. The pager is $PAGER, or DefaultPager
+// when that is unset; PAGER= or PAGER=cat turns paging off. Like git, Page
+// sets LESS=FRX when LESS is unset, so a plain "less" keeps colors too.
+//
+// When s fits, stdout is piped or the pager cannot be started, s is
+// written to stdout directly.
+func Page(s string) {
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = DefaultPager
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 || args[0] == "cat" || !stdoutIsTerminal() {
+		fmt.Print(s)
+		return
+	}
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || lineCount(s, width) < height {
+		fmt.Print(s)
+		return
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	in, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		fmt.Print(s)
+		return
+	}
+
+	// Ctrl-C belongs to the pager while it runs, as it does under git.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	// Quitting the pager early closes the pipe; the write error that
+	// follows is expected.
+	_, _ = io.WriteString(in, s)
+	in.Close()
+	_ = cmd.Wait()
+}
+
+// lineCount returns how many terminal rows s takes at the given width,
+// counting long lines once per row they wrap onto.
+func lineCount(s string, width int) int {
+	n := 0
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		w := ansi.StringWidth(line)
+		if width <= 0 || w <= width {
+			n++
+			continue
+		}
+		n += (w + width - 1) / width
+	}
+	return n
+}
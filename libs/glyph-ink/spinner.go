@@ -0,0 +1,124 @@
+package ink
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	spinnerInterval = 100 * time.Millisecond // between frames
+	spinnerRotate   = 3 * time.Second        // between messages
+)
+
+// Frames the built-in themes animate the spinner with. Frames of a set
+// are the same width, so the message after them does not shift.
+var (
+	brailleFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	asciiFrames   = []string{"|", "/", "-", "\\"}
+	dotFrames     = []string{".  ", ".. ", "...", "   "}
+)
+
+// SpinnerStyle is how a theme draws the Spinner animation.
+type SpinnerStyle struct {
+	// Frames are shown in turn, one per tick.
+	Frames []string
+	// Style renders each frame, such as in the theme's accent color. Nil
+	// leaves frames plain.
+	Style func(string) string
+}
+
+// spinnerFrames returns frames unless text symbols are forced, in which
+// case a theme's non-ASCII frames give way to the ASCII ones.
+func spinnerFrames(frames []string) []string {
+	if textSymbols.Load() {
+		return asciiFrames
+	}
+	return frames
+}
+
+// Spinner animates a single status line, such as "⠙ reviewing diff…",
+// while the caller waits. It rewrites the line in place with a carriage
+// return, so it belongs on a terminal; callers decide whether w is one.
+//
+// All methods are safe on a nil *Spinner, so a caller that chose not to
+// show one can still call Stop unconditionally.
+type Spinner struct {
+	// Style renders the message, such as Theme.Muted. Nil leaves it plain.
+	Style func(string) string
+	// Frames are the animation frames, such as Theme.Spinner().Frames.
+	// Empty uses Braille dots.
+	Frames []string
+	// FrameStyle renders each frame. Nil leaves it plain.
+	FrameStyle func(string) string
+
+	w        io.Writer
+	messages []string
+
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSpinner returns a spinner that writes to w. It shows messages[0]
+// first and moves to the next message every few seconds, wrapping around,
+// so a long wait does not look stuck. With no messages it shows
+// "thinking…".
+func NewSpinner(w io.Writer, messages ...string) *Spinner {
+	if len(messages) == 0 {
+		messages = []string{"thinking…"}
+	}
+	return &Spinner{w: w, messages: messages}
+}
+
+// Start begins the animation and returns s.
+func (s *Spinner) Start() *Spinner {
+	if s == nil || s.stop != nil {
+		return s
+	}
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	go s.run()
+	return s
+}
+
+// Stop ends the animation and clears the line. It returns once the line
+// is cleared, so output written afterwards starts on a clean line. Extra
+// calls do nothing.
+func (s *Spinner) Stop() {
+	if s == nil || s.stop == nil {
+		return
+	}
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+func (s *Spinner) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	frames := s.Frames
+	if len(frames) == 0 {
+		frames = brailleFrames
+	}
+	start := time.Now()
+	for frame := 0; ; frame++ {
+		msg := s.messages[int(time.Since(start)/spinnerRotate)%len(s.messages)]
+		if s.Style != nil {
+			msg = s.Style(msg)
+		}
+		f := frames[frame%len(frames)]
+		if s.FrameStyle != nil {
+			f = s.FrameStyle(f)
+		}
+		fmt.Fprintf(s.w, "\r\x1b[K%s %s", f, msg)
+		select {
+		case <-s.stop:
+			fmt.Fprint(s.w, "\r\x1b[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -5,12 +5,46 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 // StreamPrinter writes AI-streamed text chunks to an output writer,
 // flushing after every chunk so the user sees output in real time.
 type StreamPrinter struct {
 	w io.Writer
+
+	// LightMarkdown styles inline **bold**, *italic* and `code` spans as
+	// they stream, leaving block structure alone. It is ignored when
+	// NO_COLOR is set.
+	LightMarkdown bool
+
+	// FlushInterval, when positive, coalesces chunks: output is flushed at
+	// most once per interval, or as soon as a chunk contains a newline,
+	// instead of after every chunk. Around 16ms removes most flicker from
+	// token-sized deltas without visible lag.
+	FlushInterval time.Duration
+
+	// TrimOutput drops whitespace before the first visible character and
+	// after the last one, so the answer can be used as is, for example in
+	// pin add "$(ask ...)". Whitespace after visible text is held back
+	// until more text arrives, so it only delays output, never reorders it.
+	TrimOutput bool
+
+	// Wrap, when positive, word-wraps the printed answer at this many
+	// terminal cells, holding each word back until it is complete. The
+	// text PrintStream returns is not wrapped.
+	Wrap int
+
+	// Spinner, when set, is stopped as soon as the first chunk arrives, or
+	// when the stream ends without one, so it never mixes with the answer.
+	Spinner *Spinner
+
+	mu   sync.Mutex
+	st   *printState // current stream, nil between streams
+	text string      // text printed for the current or last stream
 }
 
 // NewStreamPrinter returns a StreamPrinter writing to w.
@@ -26,21 +60,157 @@ func (p *StreamPrinter) Print(chunk string) error {
 }
 
 // PrintStream consumes a channel of string chunks and prints each one.
-// It writes a trailing newline when the channel is closed.
-func (p *StreamPrinter) PrintStream(ch <-chan string) error {
-	bw := bufio.NewWriter(p.w)
+// It writes a trailing newline when the channel is closed and returns the
+// full accumulated text (without that newline), so callers can save or
+// post-process what was shown. After a write error the rest of ch is
+// drained unprinted, so the goroutine sending on it is never left blocked.
+func (p *StreamPrinter) PrintStream(ch <-chan string) (string, error) {
+	var err error
 	for chunk := range ch {
-		if _, err := fmt.Fprint(bw, chunk); err != nil {
-			return err
+		if err == nil {
+			err = p.writeChunk(chunk)
+		}
+	}
+	if ferr := p.finish(); err == nil {
+		err = ferr
+	}
+	return p.text, err
+}
+
+// printState is the state of the stream a StreamPrinter is printing.
+type printState struct {
+	styler *mdStyler
+	trim   *trimmer
+	wrap   *wordWrapper
+	acc    strings.Builder
+	bw     *bufio.Writer
+	timer  *time.Timer // pending coalesced flush, nil when none
+	err    error       // failure of a coalesced flush
+}
+
+// writeChunk prints chunk, one of the stream PrintStream is printing.
+func (p *StreamPrinter) writeChunk(chunk string) error {
+	p.Spinner.Stop()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st := p.state()
+	if st.err != nil {
+		return st.err
+	}
+
+	if st.trim != nil {
+		if chunk = st.trim.Write(chunk); chunk == "" {
+			return nil
 		}
-		if err := bw.Flush(); err != nil {
-			return err
+	}
+	st.acc.WriteString(chunk)
+	p.text = st.acc.String()
+	out := chunk
+	if st.styler != nil {
+		out = st.styler.Write(chunk)
+	}
+	if st.wrap != nil {
+		out = st.wrap.Write(out)
+	}
+	if _, err := fmt.Fprint(st.bw, out); err != nil {
+		return err
+	}
+	switch {
+	case p.FlushInterval <= 0 || strings.Contains(out, "\n"):
+		if st.timer != nil {
+			st.timer.Stop()
+			st.timer = nil
 		}
+		return st.bw.Flush()
+	case st.timer == nil:
+		st.timer = time.AfterFunc(p.FlushInterval, func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if p.st == st && st.timer != nil {
+				st.timer = nil
+				st.err = st.bw.Flush()
+			}
+		})
+	}
+	return nil
+}
+
+// finish flushes what is left of the stream and ends the output on a new
+// line.
+func (p *StreamPrinter) finish() error {
+	p.Spinner.Stop()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st := p.state()
+	p.st = nil
+	if st.timer != nil {
+		st.timer.Stop()
+	}
+	if st.err != nil {
+		return st.err
+	}
+	if err := st.bw.Flush(); err != nil {
+		return err
+	}
+	var rest string
+	if st.styler != nil {
+		rest = st.styler.Flush()
+	}
+	if st.wrap != nil {
+		rest = st.wrap.Write(rest) + st.wrap.Flush()
+	}
+	if _, err := fmt.Fprint(p.w, rest); err != nil {
+		return err
 	}
 	// Ensure we end on a new line.
 	_, err := fmt.Fprintln(p.w)
 	return err
 }
 
+// state returns the state of the current stream, starting one if needed.
+// p.mu must be held.
+func (p *StreamPrinter) state() *printState {
+	if p.st != nil {
+		return p.st
+	}
+	p.st = &printState{bw: bufio.NewWriter(p.w)}
+	p.text = ""
+	if p.LightMarkdown && os.Getenv("NO_COLOR") == "" {
+		p.st.styler = newMDStyler()
+	}
+	if p.TrimOutput {
+		p.st.trim = &trimmer{}
+	}
+	if p.Wrap > 0 {
+		p.st.wrap = &wordWrapper{width: p.Wrap}
+	}
+	return p.st
+}
+
 // DefaultStreamPrinter is a StreamPrinter writing to os.Stdout.
 var DefaultStreamPrinter = NewStreamPrinter(os.Stdout)
+
+// trimmer removes leading and trailing whitespace from a stream of chunks.
+type trimmer struct {
+	started bool   // a visible character has been seen
+	pending string // whitespace after the last visible character
+}
+
+// Write returns the part of chunk that can be printed now.
+func (t *trimmer) Write(chunk string) string {
+	if !t.started {
+		chunk = strings.TrimLeftFunc(chunk, unicode.IsSpace)
+		if chunk == "" {
+			return ""
+		}
+		t.started = true
+	}
+	body := strings.TrimRightFunc(chunk, unicode.IsSpace)
+	if body == "" {
+		t.pending += chunk
+		return ""
+	}
+	out := t.pending + body
+	t.pending = chunk[len(body):]
+	return out
+}
@@ -0,0 +1,70 @@
+package ink
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failWriter fails every write.
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+// send returns a channel carrying chunks, closed after the last one, and
+// a channel closed once every chunk was taken.
+func send(chunks ...string) (<-chan string, <-chan struct{}) {
+	ch, sent := make(chan string), make(chan struct{})
+	go func() {
+		defer close(sent)
+		defer close(ch)
+		for _, c := range chunks {
+			ch <- c
+		}
+	}()
+	return ch, sent
+}
+
+func TestPrintStream(t *testing.T) {
+	var out strings.Builder
+	ch, _ := send("Hello", ", ", "world")
+	text, err := NewStreamPrinter(&out).PrintStream(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Hello, world" || out.String() != "Hello, world\n" {
+		t.Errorf("text %q, printed %q", text, out.String())
+	}
+}
+
+func TestPrintStreamTrim(t *testing.T) {
+	var out strings.Builder
+	p := NewStreamPrinter(&out)
+	p.TrimOutput = true
+	ch, _ := send("\n\n  ", "answer", " \n", "more", "\n\n")
+	text, err := p.PrintStream(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "answer \nmore" || out.String() != "answer \nmore\n" {
+		t.Errorf("text %q, printed %q", text, out.String())
+	}
+}
+
+func TestPrintStreamDrainsOnError(t *testing.T) {
+	chunks := make([]string, 100)
+	for i := range chunks {
+		chunks[i] = "chunk\n"
+	}
+	ch, sent := send(chunks...)
+	_, err := NewStreamPrinter(failWriter{}).PrintStream(ch)
+	if err == nil {
+		t.Error("PrintStream to a failing writer returned no error")
+	}
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the sender was left blocked after the write error")
+	}
+}
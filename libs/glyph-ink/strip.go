@@ -0,0 +1,11 @@
+package ink
+
+import "github.com/charmbracelet/x/ansi"
+
+// StripANSI returns s without escape sequences: colors and other styling,
+// cursor movement, and OSC 8 hyperlinks, of which only the text is kept.
+// Use it on output that is written to files or the clipboard, which should
+// hold plain text even when the terminal showed it styled.
+func StripANSI(s string) string {
+	return ansi.Strip(s)
+}
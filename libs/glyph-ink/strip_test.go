@@ -0,0 +1,29 @@
+package ink
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "hello, world", "hello, world"},
+		{"color", "\x1b[1;38;2;255;0;0mred\x1b[0m text", "red text"},
+		{"lipgloss", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00ff00")).Render("ok"), "ok"},
+		{"cursor movement", "\x1b[2K\rdone\x1b[1A", "\rdone"},
+		{"hyperlink BEL", "see \x1b]8;;https://example.com\x07the docs\x1b]8;;\x07.", "see the docs."},
+		{"hyperlink ST", "\x1b]8;id=1;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"newlines and tabs", "\x1b[32ma\tb\nc\x1b[0m\n", "a\tb\nc\n"},
+		{"unicode", "\x1b[35m✓ café 日本\x1b[0m", "✓ café 日本"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.in); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package ink
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	// Render colors whatever the terminal, so goldens hold the escapes.
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	os.Exit(m.Run())
+}
+
+// tableStyles names the built-in themes by the style of their tables.
+var tableStyles = []string{"ascii", "rounded", "minimal", "raw"}
+
+// sampleTable fills t with rows that exercise the width handling: styled
+// cells, wide characters, empty cells, and rows shorter and longer than
+// the header.
+func sampleTable(t *TableRenderer) *TableRenderer {
+	bold := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#50FA7B"))
+	t.Headers("ID", "TYPE", "TEXT")
+	t.Row("1792a3f0", bold.Render("cmd"), "git log --oneline")
+	t.Row("1792a3f4", "url", "https://go.dev")
+	t.Row("9c0e11aa", "note", "日本語のメモ 📌")
+	t.Row("", "", "")
+	t.Row("short")
+	t.Row("3f00b2c1", "snippet", "a much longer cell than the others", "extra cell")
+	return t
+}
+
+func TestTableGolden(t *testing.T) {
+	for _, style := range tableStyles {
+		t.Run(style, func(t *testing.T) {
+			got := sampleTable(ThemeFrom(style).Table()).String()
+			path := filepath.Join("testdata", "table_"+style+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("table differs from %s (run with -update to accept):\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestTableWithoutHeaders(t *testing.T) {
+	for _, style := range tableStyles {
+		if got := ThemeFrom(style).Table().Row("a", "b").String(); got != "" {
+			t.Errorf("%s table without headers = %q, want nothing", style, got)
+		}
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	for _, style := range tableStyles {
+		b.Run(style, func(b *testing.B) {
+			tbl := ThemeFrom(style).Table().Headers("ID", "TYPE", "TEXT", "TAGS")
+			for i := range 10_000 {
+				tbl.Row(fmt.Sprintf("%08x", i), "cmd", strings.Repeat("text ", i%12), "go,cli")
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				tbl.Render(io.Discard)
+			}
+		})
+	}
+}
+
+func TestTableFitWrap(t *testing.T) {
+	tbl := ThemeFrom("minimal").Table().Headers("ID", "TEXT", "TAG")
+	tbl.Row("1", "short", "go")
+	tbl.Row("2", "a much longer cell that has to wrap", "cli")
+	tbl.Row("3", "https://example.com/a/very/long/path", "url")
+	got := ansi.Strip(tbl.FitWrap(1, 22).String())
+	want := `ID  TEXT           TAG
+--  -------------  ---
+1   short          go 
+2   a much longer  cli
+    cell that has     
+    to wrap           
+3   https://examp  url
+    le.com/a/very     
+    /long/path        
+`
+	if got != want {
+		t.Errorf("wrapped table:\n%s\nwant:\n%s", got, want)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if w := ansi.StringWidth(line); w > 22 {
+			t.Errorf("line %q is %d cells wide, over 22", line, w)
+		}
+	}
+}
+
+func TestTableFitWrapStyled(t *testing.T) {
+	for _, style := range []string{"ascii", "rounded"} {
+		t.Run(style, func(t *testing.T) {
+			tbl := ThemeFrom(style).Table().Headers("ID", "TEXT")
+			tbl.Row("1", "plain "+red+"styled words that wrap"+ansiReset)
+			got := tbl.FitWrap(1, 20).String()
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			for _, line := range lines {
+				if w := ansi.StringWidth(line); w != 20 {
+					t.Errorf("line %q is %d cells wide, want 20", line, w)
+				}
+			}
+			// Each wrapped line of the cell ends its own style, so the
+			// border after it is not colored.
+			for _, line := range lines[3 : len(lines)-1] {
+				if strings.Contains(line, red) && !strings.Contains(line, ansiReset) {
+					t.Errorf("line %q leaves its style open", line)
+				}
+			}
+		})
+	}
+}
+
+func TestTableFitTruncates(t *testing.T) {
+	tbl := ThemeFrom("minimal").Table().Headers("ID", "TEXT").Row("1", "a much longer cell")
+	if got, want := ansi.Strip(tbl.Fit(1, 14).String()), "ID  TEXT      \n--  ----------\n1   a much lo…\n"; got != want {
+		t.Errorf("truncated table = %q, want %q", got, want)
+	}
+}
+
+func TestTableFitKeepsRows(t *testing.T) {
+	row := []string{"1", "a much longer cell"}
+	tbl := ThemeFrom("minimal").Table().Headers("ID", "TEXT").Row(row...)
+	narrow := tbl.Fit(1, 14).String()
+	if row[1] != "a much longer cell" {
+		t.Errorf("Fit changed the caller's row to %q", row)
+	}
+	if got := tbl.Fit(1, 14).String(); got != narrow {
+		t.Errorf("second render = %q, want %q", got, narrow)
+	}
+	// A wider fit shows the full text again.
+	if got := ansi.Strip(tbl.Fit(1, 40).String()); !strings.Contains(got, "a much longer cell") {
+		t.Errorf("wide table after a narrow one = %q, want the full cell", got)
+	}
+}
@@ -1,12 +1,16 @@
 package ink
 
 import (
-	"fmt"
 	"io"
 	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Theme defines the visual contract for all glyph output.
@@ -19,6 +23,30 @@ type Theme interface {
 	Success(s string) string
 	// Error renders an error message.
 	Error(s string) string
+	// Warn renders a warning.
+	Warn(s string) string
+	// Style renders s with the method named by level: "header", "muted",
+	// "success", "warn" or "error" (case-insensitive, "ok", "warning" and
+	// "err" also work). Unknown levels return s unchanged. It lets callers
+	// pick a style from data, such as a log level.
+	Style(level, s string) string
+	// Badge renders label as a short inline tag, such as an entry type in a
+	// table, in the theme's accent color. Badges are always the label's
+	// width plus two cells (none in the raw theme), so columns stay aligned.
+	Badge(label string) string
+	// BadgeColored is Badge in color, a lipgloss color such as "#50FA7B" or
+	// "2". Monochrome themes ignore color.
+	BadgeColored(label, color string) string
+	// Link renders text as a clickable link to url on terminals that
+	// support OSC 8 hyperlinks, and as "text (url)" elsewhere.
+	Link(text, url string) string
+	// RenderDiff renders a unified diff with added lines, removed lines,
+	// hunk headers and file headers styled apart. Monochrome themes only
+	// style the headers, and the raw theme returns diff unchanged.
+	RenderDiff(diff string) string
+	// Spinner returns the frames and frame color of the animated status
+	// line shown while waiting on a request.
+	Spinner() SpinnerStyle
 	// Table returns a pre-styled table renderer.
 	Table() *TableRenderer
 }
@@ -28,14 +56,23 @@ type TableRenderer struct {
 	headers []string
 	rows    [][]string
 	style   tableStyle
+
+	fitCol   int  // column Fit shrinks
+	fitWidth int  // width Fit keeps the table within; 0 for none
+	fitWrap  bool // wrap the Fit column's cells instead of truncating them
 }
 
+// minFitWidth is the narrowest Fit shrinks a column to, so that it stays
+// readable when the other columns take up most of the width.
+const minFitWidth = 10
+
 type tableStyle int
 
 const (
 	tableASCII   tableStyle = iota
 	tableRounded tableStyle = iota
 	tableMinimal tableStyle = iota
+	tableRaw     tableStyle = iota
 )
 
 func newTable(s tableStyle) *TableRenderer {
@@ -52,132 +89,284 @@ func (t *TableRenderer) Row(cols ...string) *TableRenderer {
 	return t
 }
 
+// Fit keeps the table within width terminal cells by truncating the cells
+// of column col, with an ellipsis, when the table would be wider. The
+// column is not shrunk below minFitWidth, so a very narrow width can still
+// be exceeded. A width of 0 turns fitting off. The raw style, which has no
+// columns to line up, ignores Fit.
+func (t *TableRenderer) Fit(col, width int) *TableRenderer {
+	t.fitCol, t.fitWidth, t.fitWrap = col, width, false
+	return t
+}
+
+// FitWrap is Fit that word-wraps the cells of column col onto extra lines
+// instead of truncating them, leaving the other columns blank on those
+// lines. Words wider than the column are split, and styles and hyperlinks
+// carry over to the next line.
+func (t *TableRenderer) FitWrap(col, width int) *TableRenderer {
+	t.fitCol, t.fitWidth, t.fitWrap = col, width, true
+	return t
+}
+
 func (t *TableRenderer) Render(w io.Writer) {
 	if len(t.headers) == 0 {
 		return
 	}
+	if t.style == tableRaw {
+		b := &tableBuilder{}
+		t.renderRaw(b)
+		io.WriteString(w, b.String())
+		return
+	}
 
-	// Compute column widths.
-	widths := make([]int, len(t.headers))
+	// Compute column widths. Cell widths are kept for padding, since
+	// measuring cells dominates the cost of large tables.
+	cols := len(t.headers)
+	widths := make([]int, cols)
 	for i, h := range t.headers {
-		widths[i] = len(h)
+		widths[i] = ansi.StringWidth(h)
 	}
-	for _, row := range t.rows {
+	cells := make([]int, len(t.rows)*cols)
+	for r, row := range t.rows {
 		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < cols {
+				cw := ansi.StringWidth(cell)
+				cells[r*cols+i] = cw
+				widths[i] = max(widths[i], cw)
 			}
 		}
 	}
 
+	rows := t.rows
+	if t.fitWidth > 0 && t.fitCol < cols {
+		rows, cells = t.fit(rows, widths, cells)
+	}
+
 	accent := lipgloss.Color("#7C6AF7")
 	muted := lipgloss.Color("#6C6C6C")
+	border := lipgloss.Color(*borderColor.Load())
+
+	// The table is built in one buffer and written at once.
+	lineSize := cols*3 + 1
+	for _, cw := range widths {
+		lineSize += cw
+	}
+	b := &tableBuilder{rows: rows, cols: cols, widths: widths, cells: cells}
+	b.Grow((len(rows) + 4) * lineSize * 2)
+	b.blanks = strings.Repeat(" ", slices.Max(widths))
 
 	switch t.style {
 	case tableASCII:
-		t.renderASCII(w, widths, muted)
+		t.renderASCII(b, muted)
 	case tableRounded:
-		t.renderRounded(w, widths, accent)
+		t.renderRounded(b, accent, border)
 	case tableMinimal:
-		t.renderMinimal(w, widths, muted)
+		t.renderMinimal(b, muted)
+	}
+	io.WriteString(w, b.String())
+}
+
+// fit narrows the Fit column so the table is at most fitWidth wide,
+// truncating its cells, or wrapping them for FitWrap. It updates widths to
+// match and returns the rows to render with their cell widths.
+func (t *TableRenderer) fit(rows [][]string, widths, cells []int) ([][]string, []int) {
+	cols := len(widths)
+	total := cols*3 + 1 // "| " before each cell, " |" after the last
+	if t.style == tableMinimal {
+		total = (cols - 1) * 2
+	}
+	for _, cw := range widths {
+		total += cw
+	}
+	over := total - t.fitWidth
+	if over <= 0 {
+		return rows, cells
+	}
+	c := t.fitCol
+	limit := max(widths[c]-over, min(widths[c], minFitWidth), ansi.StringWidth(t.headers[c]))
+	widths[c] = limit
+	if !t.fitWrap {
+		// The rows are the table's, and may share arrays with the
+		// caller's slices, so cut rows are copies.
+		fitted := slices.Clone(rows)
+		for r, row := range rows {
+			if c < len(row) && cells[r*cols+c] > limit {
+				fitted[r] = slices.Clone(row)
+				fitted[r][c] = ansi.Truncate(row[c], limit, "…")
+				cells[r*cols+c] = ansi.StringWidth(fitted[r][c])
+			}
+		}
+		return fitted, cells
+	}
+
+	// Wrapping adds rows, so the rows and cell widths are rebuilt.
+	wrapped := make([][]string, 0, len(rows))
+	wrappedCells := make([]int, 0, len(cells))
+	for r, row := range rows {
+		rowCells := cells[r*cols : (r+1)*cols]
+		if c >= len(row) || rowCells[c] <= limit {
+			wrapped = append(wrapped, row)
+			wrappedCells = append(wrappedCells, rowCells...)
+			continue
+		}
+		for i, line := range WrapANSI(row[c], limit) {
+			next := make([]string, cols)
+			n := len(wrappedCells)
+			if i == 0 {
+				copy(next, row)
+				wrappedCells = append(wrappedCells, rowCells...)
+			} else {
+				wrappedCells = append(wrappedCells, make([]int, cols)...)
+			}
+			next[c] = line
+			wrappedCells[n+c] = ansi.StringWidth(line)
+			wrapped = append(wrapped, next)
+		}
 	}
+	return wrapped, wrappedCells
+}
+
+// tableBuilder accumulates a rendered table along with the measurements
+// its rows are padded to.
+type tableBuilder struct {
+	strings.Builder
+	rows   [][]string // the rows to render, after fitting
+	cols   int
+	widths []int  // per column
+	cells  []int  // per cell, row by row
+	blanks string // spaces, as many as the widest column
+}
+
+// writeCell writes cell i of row r, right-padded to its column width.
+func (b *tableBuilder) writeCell(r, i int, cell string) {
+	b.WriteString(cell)
+	b.WriteString(b.blanks[:b.widths[i]-b.cells[r*b.cols+i]])
 }
 
+// pad right-pads s with spaces to n terminal cells.
 func pad(s string, n int) string {
-	if len(s) >= n {
+	w := ansi.StringWidth(s)
+	if w >= n {
 		return s
 	}
-	return s + strings.Repeat(" ", n-len(s))
+	return s + strings.Repeat(" ", n-w)
 }
 
-func (t *TableRenderer) renderASCII(w io.Writer, widths []int, muted lipgloss.Color) {
+func (t *TableRenderer) renderASCII(b *tableBuilder, muted lipgloss.Color) {
 	headerStyle := lipgloss.NewStyle().Foreground(muted).Bold(true)
-	sep := "+"
-	for _, w := range widths {
-		sep += strings.Repeat("-", w+2) + "+"
+	var sep strings.Builder
+	sep.WriteString("+")
+	for _, w := range b.widths {
+		sep.WriteString(strings.Repeat("-", w+2))
+		sep.WriteString("+")
 	}
-	fmt.Fprintln(w, sep)
-	row := "|"
+	sep.WriteString("\n")
+
+	b.WriteString(sep.String())
+	b.WriteString("|")
 	for i, h := range t.headers {
-		row += " " + headerStyle.Render(pad(h, widths[i])) + " |"
+		b.WriteString(" " + headerStyle.Render(pad(h, b.widths[i])) + " |")
 	}
-	fmt.Fprintln(w, row)
-	fmt.Fprintln(w, sep)
-	for _, r := range t.rows {
-		line := "|"
-		for i, cell := range r {
-			if i < len(widths) {
-				line += " " + pad(cell, widths[i]) + " |"
+	b.WriteString("\n")
+	b.WriteString(sep.String())
+	for r, row := range b.rows {
+		b.WriteString("|")
+		for i, cell := range row {
+			if i < b.cols {
+				b.WriteString(" ")
+				b.writeCell(r, i, cell)
+				b.WriteString(" |")
 			}
 		}
-		fmt.Fprintln(w, line)
+		b.WriteString("\n")
 	}
-	fmt.Fprintln(w, sep)
+	b.WriteString(sep.String())
 }
 
-func (t *TableRenderer) renderRounded(w io.Writer, widths []int, accent lipgloss.Color) {
+func (t *TableRenderer) renderRounded(b *tableBuilder, accent, border lipgloss.Color) {
 	headerStyle := lipgloss.NewStyle().Foreground(accent).Bold(true)
+	borderStyle := lipgloss.NewStyle().Foreground(border)
+	bar := borderStyle.Render("\u2502")
+	cellEnd := " " + bar
 
 	// Build border pieces manually for rounded look.
 	totalWidth := 0
-	for _, ww := range widths {
+	for _, ww := range b.widths {
 		totalWidth += ww + 3
 	}
 	totalWidth++
 
-	top := "\u256d" + strings.Repeat("\u2500", totalWidth-2) + "\u256e"
-	mid := "\u251c" + strings.Repeat("\u2500", totalWidth-2) + "\u2524"
-	bot := "\u2570" + strings.Repeat("\u2500", totalWidth-2) + "\u256f"
+	line := strings.Repeat("\u2500", totalWidth-2)
+	top := "\u256d" + line + "\u256e"
+	mid := "\u251c" + line + "\u2524"
+	bot := "\u2570" + line + "\u256f"
 
-	fmt.Fprintln(w, top)
-	row := "\u2502"
+	b.WriteString(borderStyle.Render(top) + "\n")
+	b.WriteString(bar)
 	for i, h := range t.headers {
-		row += " " + headerStyle.Render(pad(h, widths[i])) + " \u2502"
+		b.WriteString(" " + headerStyle.Render(pad(h, b.widths[i])) + cellEnd)
 	}
-	fmt.Fprintln(w, row)
-	fmt.Fprintln(w, mid)
-	for _, r := range t.rows {
-		line := "\u2502"
-		for i, cell := range r {
-			if i < len(widths) {
-				line += " " + pad(cell, widths[i]) + " \u2502"
+	b.WriteString("\n")
+	b.WriteString(borderStyle.Render(mid) + "\n")
+	for r, row := range b.rows {
+		b.WriteString(bar)
+		for i, cell := range row {
+			if i < b.cols {
+				b.WriteString(" ")
+				b.writeCell(r, i, cell)
+				b.WriteString(cellEnd)
 			}
 		}
-		fmt.Fprintln(w, line)
+		b.WriteString("\n")
 	}
-	fmt.Fprintln(w, bot)
+	b.WriteString(borderStyle.Render(bot) + "\n")
 }
 
-func (t *TableRenderer) renderMinimal(w io.Writer, widths []int, muted lipgloss.Color) {
+func (t *TableRenderer) renderMinimal(b *tableBuilder, muted lipgloss.Color) {
 	headerStyle := lipgloss.NewStyle().Foreground(muted)
-	row := ""
 	for i, h := range t.headers {
 		if i > 0 {
-			row += "  "
+			b.WriteString("  ")
 		}
-		row += headerStyle.Render(pad(h, widths[i]))
+		b.WriteString(headerStyle.Render(pad(h, b.widths[i])))
 	}
-	fmt.Fprintln(w, row)
+	b.WriteString("\n")
 	// Underline headers.
-	under := ""
-	for i, ww := range widths {
+	for i, ww := range b.widths {
 		if i > 0 {
-			under += "  "
+			b.WriteString("  ")
 		}
-		under += strings.Repeat("-", ww)
+		b.WriteString(strings.Repeat("-", ww))
 	}
-	fmt.Fprintln(w, under)
-	for _, r := range t.rows {
-		line := ""
-		for i, cell := range r {
-			if i < len(widths) {
+	b.WriteString("\n")
+	for r, row := range b.rows {
+		for i, cell := range row {
+			if i < b.cols {
 				if i > 0 {
-					line += "  "
+					b.WriteString("  ")
 				}
-				line += pad(cell, widths[i])
+				b.writeCell(r, i, cell)
+			}
+		}
+		b.WriteString("\n")
+	}
+}
+
+// renderRaw writes tab-separated lines with no padding or styling, for
+// piping into cut, awk and friends.
+func (t *TableRenderer) renderRaw(b *tableBuilder) {
+	b.WriteString(strings.Join(t.headers, "\t") + "\n")
+	for _, r := range t.rows {
+		if len(r) > len(t.headers) {
+			r = r[:len(t.headers)]
+		}
+		for i, cell := range r {
+			if i > 0 {
+				b.WriteString("\t")
 			}
+			b.WriteString(cell)
 		}
-		fmt.Fprintln(w, line)
+		b.WriteString("\n")
 	}
 }
 
@@ -186,6 +375,81 @@ func (t *TableRenderer) RenderToStdout() {
 	t.Render(os.Stdout)
 }
 
+// String returns the table as Render would print it.
+func (t *TableRenderer) String() string {
+	var b strings.Builder
+	t.Render(&b)
+	return b.String()
+}
+
+// ─── Border color ────────────────────────────────────────────────────────────
+
+// defaultBorderColor keeps rounded table borders muted so they do not
+// compete with the accent-colored headers.
+const defaultBorderColor = "#6C6C6C"
+
+var borderColor atomic.Pointer[string]
+
+func init() {
+	SetBorderColor("")
+}
+
+// SetBorderColor sets the color of rounded table borders, a lipgloss color
+// such as "#44475A" or "8". An empty color restores the default muted grey.
+func SetBorderColor(color string) {
+	if color == "" {
+		color = defaultBorderColor
+	}
+	borderColor.Store(&color)
+}
+
+// ─── Symbols ─────────────────────────────────────────────────────────────────
+
+var textSymbols atomic.Bool
+
+// SetSymbols selects the Success/Warn/Error prefixes used by the built-in
+// themes. "text" or "ascii" forces the color-independent "[OK]", "[WARN]"
+// and "[ERR]" prefixes, and ASCII spinner frames, in every theme; any other
+// value restores each theme's own symbols.
+func SetSymbols(mode string) {
+	switch strings.ToLower(mode) {
+	case "text", "ascii":
+		textSymbols.Store(true)
+	default:
+		textSymbols.Store(false)
+	}
+}
+
+// successPrefix returns def unless text symbols are forced.
+func successPrefix(def string) string {
+	if textSymbols.Load() {
+		return "[OK] "
+	}
+	return def
+}
+
+// warnPrefix returns def unless text symbols are forced.
+func warnPrefix(def string) string {
+	if textSymbols.Load() {
+		return "[WARN] "
+	}
+	return def
+}
+
+// errorPrefix returns def unless text symbols are forced.
+func errorPrefix(def string) string {
+	if textSymbols.Load() {
+		return "[ERR] "
+	}
+	return def
+}
+
+// foreground returns a func that renders text in color.
+func foreground(color lipgloss.Color) func(string) string {
+	style := lipgloss.NewStyle().Foreground(color)
+	return func(s string) string { return style.Render(s) }
+}
+
 // ─── ASCII theme ─────────────────────────────────────────────────────────────
 
 type asciiTheme struct{}
@@ -204,13 +468,35 @@ func (asciiTheme) Muted(s string) string {
 }
 
 func (asciiTheme) Success(s string) string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A8A8A8")).Render("[ok] " + s)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A8A8A8")).Render(successPrefix("[ok] ") + s)
+}
+
+func (asciiTheme) Warn(s string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A8A8A8")).Render(warnPrefix("[warn] ") + s)
 }
 
 func (asciiTheme) Error(s string) string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A8A8A8")).Render("[err] " + s)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A8A8A8")).Render(errorPrefix("[err] ") + s)
+}
+
+func (t asciiTheme) Badge(label string) string { return t.BadgeColored(label, "") }
+
+func (asciiTheme) BadgeColored(label, _ string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#A8A8A8")).Render("[" + label + "]")
+}
+
+func (asciiTheme) Link(text, url string) string { return link(text, url) }
+
+func (asciiTheme) RenderDiff(diff string) string {
+	return ColorDiff(diff, DiffColors{Hunk: "#6C6C6C", File: "#A8A8A8"})
 }
 
+func (asciiTheme) Spinner() SpinnerStyle {
+	return SpinnerStyle{Frames: asciiFrames, Style: foreground(lipgloss.Color("#A8A8A8"))}
+}
+
+func (t asciiTheme) Style(level, s string) string { return StyleByName(t, level, s) }
+
 func (asciiTheme) Table() *TableRenderer { return newTable(tableASCII) }
 
 // ─── Rounded theme ───────────────────────────────────────────────────────────
@@ -236,13 +522,43 @@ func (roundedTheme) Muted(s string) string {
 }
 
 func (roundedTheme) Success(s string) string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B")).Render("✓ " + s)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B")).Render(successPrefix("✓ ") + s)
+}
+
+func (roundedTheme) Warn(s string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#F1FA8C")).Render(warnPrefix("! ") + s)
 }
 
 func (roundedTheme) Error(s string) string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555")).Render("✗ " + s)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555")).Render(errorPrefix("✗ ") + s)
+}
+
+func (t roundedTheme) Badge(label string) string { return t.BadgeColored(label, "") }
+
+func (roundedTheme) BadgeColored(label, color string) string {
+	bg := lipgloss.Color(color)
+	if color == "" {
+		bg = accent
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#1E1E2E")).
+		Background(bg).
+		Padding(0, 1).
+		Render(label)
 }
 
+func (roundedTheme) Link(text, url string) string { return link(text, url) }
+
+func (roundedTheme) RenderDiff(diff string) string {
+	return ColorDiff(diff, DiffColors{Added: "#50FA7B", Removed: "#FF5555", Hunk: string(accent)})
+}
+
+func (roundedTheme) Spinner() SpinnerStyle {
+	return SpinnerStyle{Frames: spinnerFrames(brailleFrames), Style: foreground(accent)}
+}
+
+func (t roundedTheme) Style(level, s string) string { return StyleByName(t, level, s) }
+
 func (roundedTheme) Table() *TableRenderer { return newTable(tableRounded) }
 
 // ─── Minimal theme ───────────────────────────────────────────────────────────
@@ -262,26 +578,134 @@ func (minimalTheme) Muted(s string) string {
 }
 
 func (minimalTheme) Success(s string) string {
-	return lipgloss.NewStyle().Foreground(minAccent).Render("ok  " + s)
+	return lipgloss.NewStyle().Foreground(minAccent).Render(successPrefix("ok  ") + s)
+}
+
+func (minimalTheme) Warn(s string) string {
+	return lipgloss.NewStyle().Foreground(minAccent).Render(warnPrefix("warn ") + s)
 }
 
 func (minimalTheme) Error(s string) string {
-	return lipgloss.NewStyle().Foreground(minAccent).Render("err " + s)
+	return lipgloss.NewStyle().Foreground(minAccent).Render(errorPrefix("err ") + s)
+}
+
+func (t minimalTheme) Badge(label string) string { return t.BadgeColored(label, "") }
+
+func (minimalTheme) BadgeColored(label, _ string) string {
+	return lipgloss.NewStyle().Foreground(minAccent).Render("[" + label + "]")
+}
+
+func (minimalTheme) Link(text, url string) string { return link(text, url) }
+
+func (minimalTheme) RenderDiff(diff string) string {
+	return ColorDiff(diff, DiffColors{Hunk: string(minAccent)})
+}
+
+func (minimalTheme) Spinner() SpinnerStyle {
+	return SpinnerStyle{Frames: dotFrames, Style: foreground(minAccent)}
 }
 
+func (t minimalTheme) Style(level, s string) string { return StyleByName(t, level, s) }
+
 func (minimalTheme) Table() *TableRenderer { return newTable(tableMinimal) }
 
+// ─── Raw theme ───────────────────────────────────────────────────────────────
+
+// rawTheme passes text through untouched: no color, no prefixes (not even
+// text symbols), no escape sequences in links and tab-separated tables.
+type rawTheme struct{}
+
+var _ Theme = rawTheme{}
+
+func (rawTheme) Header(s string) string  { return s }
+func (rawTheme) Muted(s string) string   { return s }
+func (rawTheme) Success(s string) string { return s }
+func (rawTheme) Warn(s string) string    { return s }
+func (rawTheme) Error(s string) string   { return s }
+
+func (rawTheme) Badge(label string) string           { return label }
+func (rawTheme) BadgeColored(label, _ string) string { return label }
+func (rawTheme) Link(text, url string) string        { return plainLink(text, url) }
+func (rawTheme) RenderDiff(diff string) string       { return diff }
+func (rawTheme) Spinner() SpinnerStyle               { return SpinnerStyle{Frames: asciiFrames} }
+
+func (t rawTheme) Style(level, s string) string { return StyleByName(t, level, s) }
+
+func (rawTheme) Table() *TableRenderer { return newTable(tableRaw) }
+
+// ─── Styles by name ──────────────────────────────────────────────────────────
+
+// StyleByName is the default implementation of Theme.Style: it dispatches
+// level to the matching method of t. Themes passed to RegisterTheme can
+// implement Style by calling it.
+func StyleByName(t Theme, level, s string) string {
+	switch strings.ToLower(level) {
+	case "header":
+		return t.Header(s)
+	case "muted":
+		return t.Muted(s)
+	case "success", "ok":
+		return t.Success(s)
+	case "warn", "warning":
+		return t.Warn(s)
+	case "error", "err":
+		return t.Error(s)
+	}
+	return s
+}
+
+// ─── Registry ────────────────────────────────────────────────────────────────
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Theme{
+		"ascii":   asciiTheme{},
+		"rounded": roundedTheme{},
+		"minimal": minimalTheme{},
+		"raw":     rawTheme{},
+	}
+)
+
+// RegisterTheme makes t available to ThemeFrom under name. Names are
+// case-insensitive; registering an existing name replaces it, including the
+// built-in themes.
+func RegisterTheme(name string, t Theme) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = t
+}
+
+// ThemeNames returns the names of all registered themes, plus "auto", in
+// sorted order.
+func ThemeNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry)+1)
+	if _, ok := registry[autoTheme]; !ok {
+		names = append(names, autoTheme)
+	}
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ─── Factory ─────────────────────────────────────────────────────────────────
 
-// ThemeFrom returns a Theme for the given name.
-// Valid values: "ascii", "rounded", "minimal". Defaults to "rounded".
+// ThemeFrom returns the registered Theme for the given name.
+// Built-in values: "ascii", "rounded", "minimal", "raw", and "auto", which
+// picks rounded or ascii from the terminal (see detectTheme). Unknown names
+// fall back to "rounded".
 func ThemeFrom(name string) Theme {
-	switch strings.ToLower(name) {
-	case "ascii":
-		return asciiTheme{}
-	case "minimal":
-		return minimalTheme{}
-	default:
-		return roundedTheme{}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	name = strings.ToLower(name)
+	if t, ok := registry[name]; ok {
+		return t
+	}
+	if name == autoTheme {
+		return detectTheme()
 	}
+	return roundedTheme{}
 }
@@ -0,0 +1,188 @@
+package ink
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// Viewer shows a streamed answer in a full-screen, scrollable pane instead
+// of the scrollback: a title line on top, the text in the middle and a
+// footer with the token count and the keys. The pane follows the text as
+// it arrives until the user scrolls up.
+//
+// A Viewer is an io.Writer, so a StreamPrinter can target it and keep its
+// styling. Start the printer in a goroutine, call Done when the stream
+// ends, and Run the viewer, which returns once the user quits; text
+// written after that is dropped. Keys: arrows, pgup/pgdown and g/G
+// scroll, c copies the text (through the terminal, OSC 52), s saves it to
+// SavePath, and q quits.
+type Viewer struct {
+	// Title is shown on the first line, such as the question asked.
+	Title string
+	// SavePath is where s saves the text. Empty disables saving.
+	SavePath string
+
+	theme   Theme
+	program *tea.Program
+}
+
+// NewViewer returns a Viewer styled by theme. When stdin is not a
+// terminal, such as when the question was piped in, keys are read from
+// the controlling terminal instead.
+func NewViewer(theme Theme) *Viewer {
+	v := &Viewer{theme: theme}
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		opts = append(opts, tea.WithInputTTY())
+	}
+	v.program = tea.NewProgram(&viewerModel{v: v, follow: true}, opts...)
+	return v
+}
+
+type (
+	viewerTextMsg string
+	viewerDoneMsg string
+)
+
+// Write appends p to the text shown.
+func (v *Viewer) Write(p []byte) (int, error) {
+	v.program.Send(viewerTextMsg(p))
+	return len(p), nil
+}
+
+// Done marks the stream as finished and shows status, such as the token
+// usage, in the footer.
+func (v *Viewer) Done(status string) {
+	v.program.Send(viewerDoneMsg(status))
+}
+
+// Run shows the viewer until the user quits.
+func (v *Viewer) Run() error {
+	_, err := v.program.Run()
+	return err
+}
+
+// viewerModel is the bubbletea model behind a Viewer.
+type viewerModel struct {
+	v      *Viewer
+	vp     viewport.Model
+	ready  bool
+	text   strings.Builder
+	done   bool
+	status string // footer text once done
+	note   string // result of the last key, such as "copied"
+	follow bool   // keep the newest text in view
+}
+
+func (m *viewerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *viewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// One line each for the title and the footer.
+		if !m.ready {
+			m.vp = viewport.New(msg.Width, max(msg.Height-2, 1))
+			m.ready = true
+		} else {
+			m.vp.Width, m.vp.Height = msg.Width, max(msg.Height-2, 1)
+		}
+		m.refresh()
+		return m, nil
+	case viewerTextMsg:
+		m.text.WriteString(string(msg))
+		m.refresh()
+		return m, nil
+	case viewerDoneMsg:
+		m.done, m.status = true, string(msg)
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "c":
+			m.note = "copied"
+			if _, err := osc52.New(m.plain()).WriteTo(os.Stderr); err != nil {
+				m.note = "copy failed: " + err.Error()
+			}
+			return m, nil
+		case "s":
+			m.note = m.save()
+			return m, nil
+		case "g", "home":
+			m.vp.GotoTop()
+		case "G", "end":
+			m.vp.GotoBottom()
+		default:
+			var cmd tea.Cmd
+			m.vp, cmd = m.vp.Update(msg)
+			m.follow = m.vp.AtBottom()
+			return m, cmd
+		}
+		m.follow = m.vp.AtBottom()
+		return m, nil
+	}
+	return m, nil
+}
+
+// refresh re-wraps the text to the pane's width.
+func (m *viewerModel) refresh() {
+	if !m.ready {
+		return
+	}
+	m.vp.SetContent(strings.Join(WrapANSI(m.text.String(), m.vp.Width), "\n"))
+	if m.follow {
+		m.vp.GotoBottom()
+	}
+}
+
+// plain returns the text shown, without styling.
+func (m *viewerModel) plain() string {
+	return strings.TrimRight(StripANSI(m.text.String()), "\n") + "\n"
+}
+
+// save writes the text to SavePath and describes the outcome.
+func (m *viewerModel) save() string {
+	if m.v.SavePath == "" {
+		return "nowhere to save"
+	}
+	if err := os.WriteFile(m.v.SavePath, []byte(m.plain()), 0o644); err != nil {
+		return "save failed: " + err.Error()
+	}
+	return "saved to " + m.v.SavePath
+}
+
+func (m *viewerModel) View() string {
+	if !m.ready {
+		return ""
+	}
+	theme := m.v.theme
+	title := theme.Header(ansi.Truncate(m.v.Title, m.vp.Width, "…"))
+
+	status := m.status
+	if !m.done {
+		// About four characters per token, as the prompt size checks
+		// estimate it.
+		status = fmt.Sprintf("streaming… ~%d tokens", (len(ansi.Strip(m.text.String()))+3)/4)
+	}
+	keys := "↑/↓ scroll · c copy · q quit"
+	if m.v.SavePath != "" {
+		keys = "↑/↓ scroll · c copy · s save · q quit"
+	}
+	parts := []string{status}
+	if m.note != "" {
+		parts = append(parts, m.note)
+	}
+	parts = append(parts, fmt.Sprintf("%d%%", int(m.vp.ScrollPercent()*100)), keys)
+	footer := theme.Muted(ansi.Truncate(strings.Join(parts, " · "), m.vp.Width, "…"))
+
+	return title + "\n" + m.vp.View() + "\n" + footer
+}
@@ -0,0 +1,235 @@
+package ink
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Wrap word-wraps s to lines of at most width terminal cells. Widths are
+// measured per grapheme, so wide characters such as CJK and emoji count as
+// two cells, and escape sequences count as zero. Words longer than width
+// (URLs, hashes) are split at the width. Existing newlines are kept. A width
+// below 1 returns s as a single line.
+//
+// Wrap does not carry styles across lines; use WrapANSI for styled text
+// whose lines are printed or padded independently.
+func Wrap(s string, width int) []string {
+	if width < 1 {
+		return strings.Split(s, "\n")
+	}
+	return strings.Split(ansi.Wrap(s, width, ""), "\n")
+}
+
+// WrapANSI is Wrap for styled text: a style or OSC 8 hyperlink still open
+// at the end of a line is closed there and re-opened at the start of the
+// next one, so every line renders correctly on its own, for example inside
+// a table cell.
+func WrapANSI(s string, width int) []string {
+	lines := Wrap(s, width)
+	var (
+		active []string // SGR sequences in effect since the last reset
+		link   string   // the open hyperlink's opening sequence, if any
+	)
+	for i, line := range lines {
+		prefix := strings.Join(active, "") + link
+		active = sgrState(active, line)
+		link = linkState(link, line)
+		if link != "" {
+			line += linkClose
+		}
+		if len(active) > 0 {
+			line += ansiReset
+		}
+		lines[i] = prefix + line
+	}
+	return lines
+}
+
+// wordWrapper word-wraps text that arrives in pieces, as a StreamPrinter
+// prints it. A word is held back until the space or newline after it
+// shows where it ends, so it can move to the next line whole. Words longer
+// than width are split at the width, as Wrap splits them. Escape sequences
+// count as zero cells and stay in place: a terminal keeps styles across
+// the line breaks it inserts.
+type wordWrapper struct {
+	width  int
+	col    int             // cells on the current line
+	spaces int             // spaces after the last word, not yet written
+	word   strings.Builder // the word being received
+}
+
+// Write returns the part of chunk that can be printed now.
+func (w *wordWrapper) Write(chunk string) string {
+	var out strings.Builder
+	// Bytes, not runes: a chunk may end partway through a character, and
+	// the word keeps its bytes until the rest arrives.
+	for i := range len(chunk) {
+		switch c := chunk[i]; c {
+		case '\n':
+			// Spaces at the end of a line are dropped.
+			w.writeWord(&out)
+			out.WriteByte('\n')
+			w.col, w.spaces = 0, 0
+		case ' ':
+			w.writeWord(&out)
+			w.spaces++
+		default:
+			w.word.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// Flush returns the word still held back, at the end of the stream.
+func (w *wordWrapper) Flush() string {
+	var out strings.Builder
+	w.writeWord(&out)
+	return out.String()
+}
+
+// writeWord writes the pending spaces and word to out, starting a new line
+// first if the word does not fit on the current one.
+func (w *wordWrapper) writeWord(out *strings.Builder) {
+	word := w.word.String()
+	if word == "" {
+		return
+	}
+	w.word.Reset()
+	n := ansi.StringWidth(word)
+	if n == 0 {
+		// Only escape sequences, such as the start of a style.
+		out.WriteString(word)
+		return
+	}
+	if w.col > 0 && w.col+w.spaces+n > w.width {
+		out.WriteByte('\n')
+		w.col = 0
+	} else {
+		out.WriteString(strings.Repeat(" ", w.spaces))
+		w.col += w.spaces
+	}
+	w.spaces = 0
+	if w.col+n > w.width {
+		lines := strings.Split(ansi.Hardwrap(word, w.width-w.col, false), "\n")
+		word = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			out.WriteString(line + "\n")
+		}
+		w.col, n = 0, ansi.StringWidth(word)
+	}
+	out.WriteString(word)
+	w.col += n
+}
+
+// ansiReset clears all SGR attributes.
+const ansiReset = "\x1b[0m"
+
+// linkClose ends an OSC 8 hyperlink.
+const linkClose = "\x1b]8;;\x1b\\"
+
+// linkState returns the opening sequence of the hyperlink open after line,
+// given the one open before it, or "" when none is.
+func linkState(open, line string) string {
+	for {
+		start := strings.Index(line, "\x1b]8;")
+		if start < 0 {
+			return open
+		}
+		rest := line[start:]
+		// The sequence ends with BEL or with ESC \.
+		end := strings.IndexAny(rest[1:], "\a\x1b")
+		if end < 0 {
+			return open
+		}
+		end++
+		body := rest[len("\x1b]8;"):end]
+		if rest[end] == '\x1b' {
+			end++
+		}
+		seq := rest[:min(end+1, len(rest))]
+		// The body is params;URI, and an empty URI closes the link.
+		if _, uri, _ := strings.Cut(body, ";"); uri != "" {
+			open = seq
+		} else {
+			open = ""
+		}
+		line = rest[len(seq):]
+	}
+}
+
+// sgrState returns the SGR sequences in effect after line, given those in
+// effect before it. A reset (ESC[m or ESC[0m) clears the list, an "off"
+// code such as 22 or 39 drops the sequences it cancels, and any other SGR
+// sequence replaces the one setting the same attribute.
+func sgrState(active []string, line string) []string {
+	for {
+		start := strings.Index(line, "\x1b[")
+		if start < 0 {
+			return active
+		}
+		end := start + 2
+		for end < len(line) && (line[end] == ';' || line[end] == ':' || (line[end] >= '0' && line[end] <= '9')) {
+			end++
+		}
+		if end == len(line) {
+			return active
+		}
+		if line[end] == 'm' {
+			seq, params := line[start:end+1], line[start+2:end]
+			first, _, _ := strings.Cut(params, ";")
+			switch {
+			case first == "" || first == "0":
+				active = active[:0]
+			default:
+				slot, off := sgrSlot(first)
+				active = dropSlot(active, slot)
+				if !off {
+					active = append(active, seq)
+				}
+			}
+		}
+		line = line[end+1:]
+	}
+}
+
+// sgrSlot names the attribute an SGR parameter sets and reports whether it
+// turns that attribute off.
+func sgrSlot(param string) (slot string, off bool) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return param, false
+	}
+	switch {
+	case n == 1 || n == 2 || n == 22:
+		return "intensity", n == 22
+	case n == 3 || n == 23:
+		return "italic", n == 23
+	case n == 4 || n == 24:
+		return "underline", n == 24
+	case n == 5 || n == 25:
+		return "blink", n == 25
+	case n == 7 || n == 27:
+		return "reverse", n == 27
+	case n == 9 || n == 29:
+		return "strike", n == 29
+	case n >= 30 && n <= 39, n >= 90 && n <= 97:
+		return "fg", n == 39
+	case n >= 40 && n <= 49, n >= 100 && n <= 107:
+		return "bg", n == 49
+	}
+	return param, false
+}
+
+// dropSlot removes the sequences in active that set slot.
+func dropSlot(active []string, slot string) []string {
+	kept := active[:0]
+	for _, seq := range active {
+		first, _, _ := strings.Cut(seq[2:len(seq)-1], ";")
+		if s, _ := sgrSlot(first); s != slot {
+			kept = append(kept, seq)
+		}
+	}
+	return kept
+}
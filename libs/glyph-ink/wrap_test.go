@@ -0,0 +1,142 @@
+package ink
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	red  = "\x1b[31m"
+	bold = "\x1b[1m"
+	url  = "\x1b]8;;https://go.dev\x1b\\"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  []string
+	}{
+		{"fits", "git log", 10, []string{"git log"}},
+		{"words", "the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"newlines kept", "one\ntwo three", 5, []string{"one", "two", "three"}},
+		{"long word split", "see https://go.dev/doc", 8, []string{"see", "https://", "go.dev/d", "oc"}},
+		{"wide characters", "日本語のメモです", 6, []string{"日本語", "のメモ", "です"}},
+		{"no width", "the quick brown fox", 0, []string{"the quick brown fox"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Wrap(tt.s, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapANSI(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  []string
+	}{
+		{
+			"style across a break",
+			"a " + red + "red sentence here" + ansiReset + " ok",
+			8,
+			[]string{"a " + red + "red" + ansiReset, red + "sentence" + ansiReset, red + "here" + ansiReset + " ok"},
+		},
+		{
+			"styles stack",
+			bold + "bold " + red + "and red" + ansiReset,
+			5,
+			[]string{bold + "bold" + ansiReset, bold + red + "and" + ansiReset, bold + red + "red" + ansiReset},
+		},
+		{
+			"hyperlink across a break",
+			url + "go docs" + linkClose + " now",
+			4,
+			[]string{url + "go" + linkClose, url + "docs" + linkClose, "now"},
+		},
+		{
+			"long styled word split",
+			red + "0123456789ab" + ansiReset,
+			5,
+			[]string{red + "01234" + ansiReset, red + "56789" + ansiReset, red + "ab" + ansiReset},
+		},
+		{"plain text unchanged", "the quick brown fox", 10, []string{"the quick", "brown fox"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapANSI(tt.s, tt.width)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WrapANSI = %q, want %q", got, tt.want)
+			}
+			for _, line := range got {
+				if w := ansi.StringWidth(line); w > tt.width {
+					t.Errorf("line %q is %d cells wide, over %d", line, w, tt.width)
+				}
+			}
+			if plain := ansi.Strip(strings.Join(got, " ")); plain != strings.Join(Wrap(ansi.Strip(tt.s), tt.width), " ") {
+				t.Errorf("WrapANSI text %q differs from Wrap of the plain text", plain)
+			}
+		})
+	}
+}
+
+func TestWordWrapper(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"words", "the quick brown fox jumps", 10, "the quick\nbrown fox\njumps"},
+		{"newlines reset", "one two\nthree four five", 10, "one two\nthree four\nfive"},
+		{"trailing spaces dropped", "ab   \ncd", 10, "ab\ncd"},
+		{"long word split", "see https://go.dev/doc/install", 8, "see\nhttps://\ngo.dev/d\noc/insta\nll"},
+		{"long word after text", "abc 0123456789", 6, "abc\n012345\n6789"},
+		{"wide characters", "日本語 のメモ です", 7, "日本語\nのメモ\nです"},
+		{"styles cost nothing", "a " + red + "red" + ansiReset + " word", 10, "a " + red + "red" + ansiReset + " word"},
+		{"style between words", "alpha " + bold + "beta gamma" + ansiReset, 10, "alpha " + bold + "beta\ngamma" + ansiReset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Whole, and one byte at a time as a stream might split it.
+			whole := &wordWrapper{width: tt.width}
+			if got := whole.Write(tt.s) + whole.Flush(); got != tt.want {
+				t.Errorf("whole = %q, want %q", got, tt.want)
+			}
+			split := &wordWrapper{width: tt.width}
+			var b strings.Builder
+			for i := range len(tt.s) {
+				b.WriteString(split.Write(tt.s[i : i+1]))
+			}
+			b.WriteString(split.Flush())
+			if b.String() != tt.want {
+				t.Errorf("byte by byte = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintStreamWrap(t *testing.T) {
+	var out strings.Builder
+	p := NewStreamPrinter(&out)
+	p.Wrap = 12
+	ch, _ := send("Wrapping ke", "eps words whole", " even when streamed")
+	text, err := p.PrintStream(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Wrapping keeps words whole even when streamed" {
+		t.Errorf("text = %q, want it unwrapped", text)
+	}
+	if want := "Wrapping\nkeeps words\nwhole even\nwhen\nstreamed\n"; out.String() != want {
+		t.Errorf("printed %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,120 @@
+package mind
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// defaultAzureAPIVersion is the api-version used when azure.api_version is
+// empty.
+const defaultAzureAPIVersion = "2024-10-21"
+
+// azureClient talks to an Azure OpenAI deployment. The API is OpenAI's
+// chat completions with the deployment in the URL and an api-key header.
+type azureClient struct {
+	http       *http.Client
+	maxBody    int64  // see core.Config.MaxRequestBytes
+	url        string // chat completions URL, with api-version
+	apiKey     string
+	deployment string
+	model      string
+	systemRole string
+	stop       []string
+	seed       *int64
+	format     ResponseFormat
+}
+
+// newAzureClient checks the [azure] settings of cfg and returns the client.
+func newAzureClient(cfg core.Config, httpClient *http.Client, role string, format ResponseFormat) (*azureClient, error) {
+	az := cfg.Azure
+	if cfg.APIKey == "" {
+		return nil, &core.AppError{Msg: "api_key is required for azure provider"}
+	}
+	if az.Deployment == "" {
+		return nil, &core.AppError{Msg: "azure.deployment is required for azure provider"}
+	}
+	if err := checkStop("azure", cfg.Stop); err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimRight(az.Endpoint, "/")
+	switch {
+	case endpoint != "":
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, &core.AppError{Msg: "azure.endpoint must be a URL such as https://my-resource.openai.azure.com"}
+		}
+	case az.Resource != "":
+		endpoint = "https://" + az.Resource + ".openai.azure.com"
+	default:
+		return nil, &core.AppError{Msg: "azure.resource or azure.endpoint is required for azure provider"}
+	}
+	version := az.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+
+	return &azureClient{
+		http:       httpClient,
+		maxBody:    cfg.MaxRequestBytes,
+		url:        endpoint + "/openai/deployments/" + url.PathEscape(az.Deployment) + "/chat/completions?api-version=" + url.QueryEscape(version),
+		apiKey:     cfg.APIKey,
+		deployment: az.Deployment,
+		model:      ResolveModel(cfg),
+		systemRole: role,
+		stop:       cfg.Stop,
+		seed:       cfg.Seed,
+		format:     format,
+	}, nil
+}
+
+func (c *azureClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
+	body, size, err := c.post(ctx, system, user, nil)
+	if err != nil {
+		return nil, err
+	}
+	stream, ch := newStream("azure", c.model)
+	stream.requestBytes = size
+	go sseStream(ctx, body, stream, ch, openAIDelta(stream))
+	return stream, nil
+}
+
+func (c *azureClient) StreamEvents(ctx context.Context, system, user string, tools []Tool) (*EventStream, error) {
+	body, size, err := c.post(ctx, system, user, tools)
+	if err != nil {
+		return nil, err
+	}
+	events, stream, ch := newEventStream("azure", c.model)
+	stream.requestBytes = size
+	go sseStream(ctx, body, stream, ch, openAIEvents(stream))
+	return events, nil
+}
+
+// post sends the chat completion request to the deployment and returns
+// the streaming response body and the size of the request.
+func (c *azureClient) post(ctx context.Context, system, user string, tools []Tool) (io.ReadCloser, int, error) {
+	payload := groqRequest{
+		// Azure ignores the model: the deployment decides it.
+		Model: c.deployment,
+		Messages: []chatMessage{
+			{Role: c.systemRole, Content: system},
+			{Role: "user", Content: user},
+		},
+		Stream:         true,
+		StreamOptions:  streamOptions{IncludeUsage: true},
+		Stop:           c.stop,
+		Seed:           c.seed,
+		ResponseFormat: c.format.openAI(),
+		Tools:          openAITools(tools),
+	}
+
+	body, size, err := doPost(ctx, c.http, c.url, map[string]string{"api-key": c.apiKey}, payload, c.maxBody)
+	if err != nil {
+		return nil, size, formatRejected("azure", c.deployment, c.format, err)
+	}
+	return body, size, nil
+}
@@ -1,104 +1,255 @@
 package mind
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"io"
+	"net/http"
 	"strings"
+
+	core "github.com/reky0/glyph-core"
 )
 
 // ─── Claude (Anthropic) client ───────────────────────────────────────────────
 //
 // Anthropic's SSE format differs from OpenAI's:
 //   - Each event is preceded by an "event: <type>" line.
-//   - Text deltas arrive on "content_block_delta" events.
+//   - Text deltas arrive on "content_block_delta" events, as do the
+//     arguments of a tool call, after a "content_block_start" event that
+//     names the tool.
 //   - The stream ends with a "message_stop" event (no "[DONE]" sentinel).
+//   - A failure mid-stream, such as an overload, arrives as an "error" event.
 
 const anthropicAPIURL = "https://api.anthropic.com/v1/messages"
 const anthropicVersion = "2023-06-01"
 const claudeMaxTokens = 8192
 
 type claudeClient struct {
-	apiKey string
-	model  string
+	http    *http.Client
+	maxBody int64 // see core.Config.MaxRequestBytes
+	apiKey  string
+	model   string
+	stop    []string
+	format  ResponseFormat
 }
 
 type claudeRequest struct {
-	Model     string         `json:"model"`
-	MaxTokens int            `json:"max_tokens"`
-	System    string         `json:"system"`
-	Messages  []chatMessage  `json:"messages"`
-	Stream    bool           `json:"stream"`
+	Model         string        `json:"model"`
+	MaxTokens     int           `json:"max_tokens"`
+	System        string        `json:"system"`
+	Messages      []chatMessage `json:"messages"`
+	Stream        bool          `json:"stream"`
+	StopSequences []string      `json:"stop_sequences,omitempty"`
+	Tools         []claudeTool  `json:"tools,omitempty"`
 }
 
 // SSE event payloads we care about.
 type claudeContentBlockDelta struct {
+	Index int `json:"index"`
 	Delta struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"` // of an input_json_delta
 	} `json:"delta"`
 }
 
-func (c *claudeClient) Stream(ctx context.Context, system, user string) (<-chan string, error) {
-	payload := claudeRequest{
-		Model:     c.model,
-		MaxTokens: claudeMaxTokens,
-		System:    system,
-		Messages:  []chatMessage{{Role: "user", Content: user}},
-		Stream:    true,
-	}
+type claudeUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
 
-	body, err := doPost(ctx, anthropicAPIURL, map[string]string{
-		"x-api-key":         c.apiKey,
-		"anthropic-version": anthropicVersion,
-	}, payload)
+// message_start carries the input token count; message_delta carries the
+// running output token count.
+type claudeMessageStart struct {
+	Message struct {
+		Usage claudeUsage `json:"usage"`
+	} `json:"message"`
+}
+
+type claudeMessageDelta struct {
+	Delta struct {
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage claudeUsage `json:"usage"`
+}
+
+// claudeError is the payload of an "error" event.
+type claudeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *claudeClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
+	body, size, err := c.post(ctx, system, user, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	ch := make(chan string, 64)
+	stream, ch := newStream("claude", c.model)
+	stream.requestBytes = size
 	go func() {
 		defer close(ch)
 		defer body.Close()
 
-		scanner := bufio.NewScanner(body)
-		var currentEvent string
-
-		for scanner.Scan() {
+		events := newSSEReader(body)
+		for events.Next() {
 			select {
 			case <-ctx.Done():
+				stream.Fail(ctx, ctx.Err())
 				return
 			default:
 			}
 
-			line := scanner.Text()
+			event := events.Event()
+			if _, err := claudeStatus(stream, event); err != nil {
+				stream.Fail(ctx, err)
+				return
+			}
+			switch event.Name {
+			case "content_block_delta":
+				var delta claudeContentBlockDelta
+				if err := json.Unmarshal([]byte(event.Data), &delta); err != nil {
+					continue
+				}
+				if delta.Delta.Type == "text_delta" && delta.Delta.Text != "" {
+					select {
+					case ch <- delta.Delta.Text:
+					case <-ctx.Done():
+						stream.Fail(ctx, ctx.Err())
+						return
+					}
+				}
 
-			switch {
-			case strings.HasPrefix(line, "event:"):
-				currentEvent = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case "message_stop":
+				return
+			}
+		}
+		stream.Fail(ctx, events.Err())
+	}()
+	return stream, nil
+}
 
-			case strings.HasPrefix(line, "data:"):
-				payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+func (c *claudeClient) StreamEvents(ctx context.Context, system, user string, tools []Tool) (*EventStream, error) {
+	body, size, err := c.post(ctx, system, user, tools)
+	if err != nil {
+		return nil, err
+	}
 
-				switch currentEvent {
-				case "content_block_delta":
-					var delta claudeContentBlockDelta
-					if err := json.Unmarshal([]byte(payload), &delta); err != nil {
-						continue
-					}
-					if delta.Delta.Type == "text_delta" && delta.Delta.Text != "" {
-						select {
-						case ch <- delta.Delta.Text:
-						case <-ctx.Done():
-							return
-						}
-					}
+	out, stream, ch := newEventStream("claude", c.model)
+	stream.requestBytes = size
+	go func() {
+		defer close(ch)
+		defer body.Close()
 
-				case "message_stop":
-					return
+		events := newSSEReader(body)
+		for events.Next() {
+			select {
+			case <-ctx.Done():
+				stream.Fail(ctx, ctx.Err())
+				return
+			default:
+			}
+
+			event := events.Event()
+			reason, err := claudeStatus(stream, event)
+			if err != nil {
+				stream.Fail(ctx, err)
+				return
+			}
+			var emit []Event
+			switch event.Name {
+			case "content_block_start":
+				var block claudeContentBlockStart
+				if err := json.Unmarshal([]byte(event.Data), &block); err == nil && block.ContentBlock.Type == "tool_use" {
+					emit = append(emit, Event{Kind: EventToolCall, ToolCall: ToolCallDelta{
+						Index: block.Index,
+						ID:    block.ContentBlock.ID,
+						Name:  block.ContentBlock.Name,
+					}})
 				}
+
+			case "content_block_delta":
+				var delta claudeContentBlockDelta
+				if err := json.Unmarshal([]byte(event.Data), &delta); err != nil {
+					continue
+				}
+				switch {
+				case delta.Delta.Type == "text_delta" && delta.Delta.Text != "":
+					emit = append(emit, Event{Kind: EventText, Text: delta.Delta.Text})
+				case delta.Delta.Type == "input_json_delta" && delta.Delta.PartialJSON != "":
+					emit = append(emit, Event{Kind: EventToolCall, ToolCall: ToolCallDelta{
+						Index:     delta.Index,
+						Arguments: delta.Delta.PartialJSON,
+					}})
+				}
+
+			case "message_delta":
+				if reason != "" {
+					emit = append(emit, Event{Kind: EventFinish, FinishReason: claudeFinishReason(reason)})
+				}
+
+			case "message_stop":
+				return
+			}
+			if !sendEvents(ctx, ch, emit) {
+				stream.Fail(ctx, ctx.Err())
+				return
 			}
 		}
+		stream.Fail(ctx, events.Err())
 	}()
-	return ch, nil
+	return out, nil
+}
+
+// post sends the messages request and returns the streaming response body
+// and the size of the request.
+func (c *claudeClient) post(ctx context.Context, system, user string, tools []Tool) (io.ReadCloser, int, error) {
+	payload := claudeRequest{
+		Model:         c.model,
+		MaxTokens:     claudeMaxTokens,
+		System:        NewPromptBuilder(system).AddSection("", c.format.instruction()).String(),
+		Messages:      []chatMessage{{Role: "user", Content: user}},
+		Stream:        true,
+		StopSequences: c.stop,
+		Tools:         claudeTools(tools),
+	}
+
+	return doPost(ctx, c.http, anthropicAPIURL, map[string]string{
+		"x-api-key":         c.apiKey,
+		"anthropic-version": anthropicVersion,
+	}, payload, c.maxBody)
+}
+
+// claudeStatus records what the message_start and message_delta events of
+// a response report on stream, and returns the stop reason a
+// message_delta carries. An "error" event is returned as an error.
+func claudeStatus(stream *Stream, event sseEvent) (string, error) {
+	payload := []byte(strings.TrimSpace(event.Data))
+	switch event.Name {
+	case "message_start":
+		var start claudeMessageStart
+		if err := json.Unmarshal(payload, &start); err == nil {
+			stream.usage.InputTokens = start.Message.Usage.InputTokens
+		}
+
+	case "message_delta":
+		var delta claudeMessageDelta
+		if err := json.Unmarshal(payload, &delta); err == nil {
+			stream.usage.OutputTokens = delta.Usage.OutputTokens
+			stream.truncated = delta.Delta.StopReason == "max_tokens"
+			stream.filtered = delta.Delta.StopReason == "refusal"
+			return delta.Delta.StopReason, nil
+		}
+
+	case "error":
+		var apiErr claudeError
+		msg := string(payload)
+		if err := json.Unmarshal(payload, &apiErr); err == nil && apiErr.Error.Message != "" {
+			msg = apiErr.Error.Message
+		}
+		return "", &core.AppError{Msg: "claude error: " + msg}
+	}
+	return "", nil
 }
@@ -3,61 +3,217 @@ package mind
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	core "github.com/reky0/glyph-core"
 )
 
 // Client can stream a response for a given system + user prompt.
 type Client interface {
-	// Stream sends a prompt and returns a channel of text chunks.
-	// The caller must drain the channel. The channel is closed when
-	// the stream ends. Any error after the channel is opened is
+	// Stream sends a prompt and returns the in-flight response.
+	// The caller must drain its C channel, which is closed when the
+	// stream ends. Any error after the channel is opened is
 	// communicated by closing the channel; inspect the returned error
 	// only for startup failures.
-	Stream(ctx context.Context, system, user string) (<-chan string, error)
+	Stream(ctx context.Context, system, user string) (*Stream, error)
+}
+
+// knownProviders lists the accepted ai_provider values.
+var knownProviders = []string{"groq", "ollama", "claude", "azure"}
+
+// KnownProviders returns the accepted ai_provider values.
+func KnownProviders() []string {
+	return append([]string(nil), knownProviders...)
+}
+
+// IsKnownProvider reports whether name is an accepted ai_provider value.
+func IsKnownProvider(name string) bool {
+	for _, p := range knownProviders {
+		if strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresAPIKey reports whether provider needs api_key to be set. Ollama
+// runs locally and does not.
+func RequiresAPIKey(provider string) bool {
+	return normalizeProvider(provider) != "ollama"
+}
+
+// APIKeyFor returns the API key cfg holds for provider: api_key when
+// provider is ai_provider, otherwise api_keys. The key of one provider is
+// never returned for another, so it cannot leak to a fallback's vendor.
+func APIKeyFor(cfg core.Config, provider string) string {
+	provider = normalizeProvider(provider)
+	if provider == normalizeProvider(cfg.AIProvider) && cfg.APIKey != "" {
+		return cfg.APIKey
+	}
+	for name, key := range cfg.APIKeys {
+		if normalizeProvider(name) == provider {
+			return key
+		}
+	}
+	return ""
+}
+
+// FallbackConfig returns the config a fallback spec, a "provider:model"
+// entry of cfg.Fallbacks, is sent with: cfg with the provider, the model
+// and the API key swapped, and no fallbacks of its own.
+func FallbackConfig(cfg core.Config, spec string) core.Config {
+	provider, model, _ := strings.Cut(spec, ":")
+	fcfg := cfg
+	fcfg.AIProvider, fcfg.AIModel, fcfg.Fallbacks = provider, model, nil
+	fcfg.APIKey = APIKeyFor(cfg, provider)
+	return fcfg
 }
 
 // NewClientFromConfig constructs the appropriate Client from configuration.
+// The resolved model is checked with CheckModel: a model that belongs to
+// another provider is an error, an unrecognized one is accepted. With
+// fallbacks configured, the client tries each of them in turn when the
+// configured provider fails before its first chunk (see fallbackClient).
+// With a JSON response format, answers that are not valid JSON end with an
+// error (see jsonClient).
 func NewClientFromConfig(cfg core.Config) (Client, error) {
+	client, err := newFallbackClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if rf, _ := responseFormat(cfg); rf.JSON {
+		return jsonClient{client}, nil
+	}
+	return client, nil
+}
+
+// newFallbackClient constructs the client for cfg.AIProvider, wrapped in a
+// fallbackClient when fallbacks are configured.
+func newFallbackClient(cfg core.Config) (Client, error) {
+	primary, err := newProviderClient(cfg)
+	if err != nil || len(cfg.Fallbacks) == 0 {
+		return primary, err
+	}
+	fc := &fallbackClient{
+		clients: []Client{primary},
+		labels:  []string{cfg.AIProvider},
+	}
+	for _, spec := range cfg.Fallbacks {
+		provider, _, _ := strings.Cut(spec, ":")
+		if !IsKnownProvider(provider) {
+			return nil, &core.AppError{
+				Msg: fmt.Sprintf("fallback %q: unknown provider %q (valid: %s)", spec, provider, strings.Join(knownProviders, ", ")),
+			}
+		}
+		fcfg := FallbackConfig(cfg, spec)
+		if RequiresAPIKey(provider) && fcfg.APIKey == "" {
+			name := normalizeProvider(provider)
+			return nil, &core.AppError{
+				Msg: fmt.Sprintf("fallback %q: no API key for %s; set api_keys.%s (api_key is only sent to %s)", spec, name, name, normalizeProvider(cfg.AIProvider)),
+			}
+		}
+		client, err := newProviderClient(fcfg)
+		if err != nil {
+			return nil, &core.AppError{Msg: fmt.Sprintf("fallback %q", spec), Err: err}
+		}
+		fc.clients = append(fc.clients, client)
+		fc.labels = append(fc.labels, spec)
+	}
+	return fc, nil
+}
+
+// newProviderClient constructs the Client for cfg.AIProvider alone.
+func newProviderClient(cfg core.Config) (Client, error) {
+	cfg.APIKey = APIKeyFor(cfg, cfg.AIProvider)
+	model := ResolveModel(cfg)
+	if err := CheckModel(cfg.AIProvider, model); err != nil && !errors.Is(err, ErrUnrecognizedModel) {
+		return nil, err
+	}
+	role, err := systemRole(cfg.SystemRole)
+	if err != nil {
+		return nil, err
+	}
+	format, err := responseFormat(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Seed != nil && *cfg.Seed < 0 {
+		return nil, &core.AppError{Msg: fmt.Sprintf("seed must be 0 or more (got %d)", *cfg.Seed)}
+	}
+	httpClient, err := HTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 	switch strings.ToLower(cfg.AIProvider) {
 	case "ollama":
 		return &ollamaClient{
-			host:  cfg.OllamaHost,
-			model: cfg.AIModel,
+			http:    httpClient,
+			maxBody: cfg.MaxRequestBytes,
+			host:    cfg.OllamaHost,
+			model:   model,
+			stop:    cfg.Stop,
+			seed:    cfg.Seed,
+			format:  format,
 		}, nil
 	case "groq", "":
 		if cfg.APIKey == "" {
 			return nil, &core.AppError{Msg: "api_key is required for groq provider"}
 		}
+		if err := checkStop("groq", cfg.Stop); err != nil {
+			return nil, err
+		}
 		return &groqClient{
-			apiKey: cfg.APIKey,
-			model:  cfg.AIModel,
+			http:       httpClient,
+			maxBody:    cfg.MaxRequestBytes,
+			apiKey:     cfg.APIKey,
+			model:      model,
+			systemRole: role,
+			stop:       cfg.Stop,
+			seed:       cfg.Seed,
+			format:     format,
 		}, nil
+	case "azure":
+		return newAzureClient(cfg, httpClient, role, format)
 	case "claude":
 		if cfg.APIKey == "" {
 			return nil, &core.AppError{Msg: "api_key is required for claude provider"}
 		}
-		model := cfg.AIModel
-		if model == "" {
-			model = "claude-sonnet-4-6"
-		}
 		return &claudeClient{
-			apiKey: cfg.APIKey,
-			model:  model,
+			http:    httpClient,
+			maxBody: cfg.MaxRequestBytes,
+			apiKey:  cfg.APIKey,
+			model:   model,
+			stop:    cfg.Stop,
+			format:  format,
 		}, nil
 	default:
 		return nil, &core.AppError{
-			Msg: fmt.Sprintf("unknown ai_provider %q (valid: groq, ollama, claude)", cfg.AIProvider),
+			Msg: fmt.Sprintf("unknown ai_provider %q (valid: %s)", cfg.AIProvider, strings.Join(knownProviders, ", ")),
 		}
 	}
 }
 
+// systemRole validates the system_role setting and returns the role to send
+// the system prompt under.
+func systemRole(role string) (string, error) {
+	switch strings.ToLower(role) {
+	case "", "system":
+		return "system", nil
+	case "developer":
+		return "developer", nil
+	}
+	return "", &core.AppError{Msg: fmt.Sprintf("unknown system_role %q (valid: system, developer)", role)}
+}
+
 // ─── shared helpers ──────────────────────────────────────────────────────────
 
 // chatMessage is the OpenAI-style message object used by both backends.
@@ -66,143 +222,358 @@ type chatMessage struct {
 	Content string `json:"content"`
 }
 
-// sseStream reads an SSE response body, emitting text deltas to ch.
-// It handles OpenAI-style `data: {...}` lines and closes ch when done.
-func sseStream(ctx context.Context, body io.ReadCloser, ch chan<- string, extractDelta func([]byte) (string, bool, error)) {
+// maxLineSize bounds a single line of a streamed response. Lines are
+// usually small deltas, but a final message can carry large fields, which
+// would overflow bufio.Scanner's 64 KiB default and end the stream early.
+const maxLineSize = 16 << 20
+
+// newLineScanner returns a line scanner over r that accepts lines of up to
+// maxLineSize bytes.
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return scanner
+}
+
+// sseStream reads an OpenAI-style SSE response body, passing the data of
+// each event to extractDelta and emitting what it returns, text deltas or
+// Events, to ch, until a "[DONE]" event. An "error" event ends the stream
+// with its data as the error. It closes ch when done, recording any read
+// or context error on stream first.
+func sseStream[T any](ctx context.Context, body io.ReadCloser, stream *Stream, ch chan<- T, extractDelta func([]byte) ([]T, bool, error)) {
 	defer close(ch)
 	defer body.Close()
 
-	scanner := bufio.NewScanner(body)
-	for scanner.Scan() {
+	events := newSSEReader(body)
+	for events.Next() {
 		select {
 		case <-ctx.Done():
+			stream.Fail(ctx, ctx.Err())
 			return
 		default:
 		}
 
-		line := scanner.Text()
-		if !strings.HasPrefix(line, "data:") {
-			continue
-		}
-		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-		if payload == "[DONE]" {
+		event := events.Event()
+		if event.Name == "error" {
+			stream.Fail(ctx, &core.AppError{Msg: "provider error: " + strings.TrimSpace(event.Data)})
 			return
 		}
-		delta, done, err := extractDelta([]byte(payload))
-		if err != nil {
-			// Silently skip malformed lines.
-			continue
-		}
-		if done {
-			return
-		}
-		if delta != "" {
-			select {
-			case ch <- delta:
-			case <-ctx.Done():
+		for _, payload := range event.payloads() {
+			if payload == "[DONE]" {
+				return
+			}
+			deltas, done, err := extractDelta([]byte(payload))
+			if err != nil {
+				// Silently skip malformed events.
+				continue
+			}
+			if !sendEvents(ctx, ch, deltas) {
+				stream.Fail(ctx, ctx.Err())
+				return
+			}
+			if done {
 				return
 			}
 		}
 	}
+	stream.Fail(ctx, events.Err())
+}
+
+// HTTPError is returned when a provider answers with an HTTP error status.
+type HTTPError struct {
+	StatusCode int
+	Body       string
 }
 
-// doPost sends a JSON POST request and returns the response body.
-func doPost(ctx context.Context, url string, headers map[string]string, payload any) (io.ReadCloser, error) {
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("mind: server returned %d: %s", e.StatusCode, e.Body)
+}
+
+// RequestTooLargeError is returned when a request body is larger than
+// max_request_bytes allows. The request is not sent.
+type RequestTooLargeError struct {
+	Size int64 // bytes in the request body
+	Max  int64 // max_request_bytes
+}
+
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf("request is %d bytes, over the max_request_bytes limit of %d; send less input or raise max_request_bytes", e.Size, e.Max)
+}
+
+// doPost sends a JSON POST request with client and returns the response
+// body and the size of the request body. A request body larger than
+// maxBody is not sent; maxBody 0 means no limit.
+func doPost(ctx context.Context, client *http.Client, url string, headers map[string]string, payload any, maxBody int64) (io.ReadCloser, int, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("mind: marshal request: %w", err)
+		return nil, 0, fmt.Errorf("mind: marshal request: %w", err)
+	}
+	if maxBody > 0 && int64(len(body)) > maxBody {
+		return nil, len(body), &RequestTooLargeError{Size: int64(len(body)), Max: maxBody}
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("mind: create request: %w", err)
+		return nil, len(body), fmt.Errorf("mind: create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	// Setting Accept-Encoding turns off net/http's transparent gzip
+	// handling, so decodeBody decodes both encodings the same way.
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// Timed out or canceled before the response began: report it
+			// as Stream.Fail does once it has.
+			return nil, len(body), contextError(ctxErr, time.Since(start))
+		}
+		return nil, len(body), fmt.Errorf("mind: request: %w", err)
+	}
+	respBody, err := decodeBody(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, len(body), err
+	}
+	if resp.StatusCode >= 400 {
+		errBody, _ := io.ReadAll(respBody)
+		respBody.Close()
+		return nil, len(body), &HTTPError{StatusCode: resp.StatusCode, Body: string(errBody)}
+	}
+	return respBody, len(body), nil
+}
+
+// decodeBody returns resp's body decoded according to its
+// Content-Encoding: gzip, deflate (zlib) or none. Closing the result
+// closes resp.Body.
+func decodeBody(resp *http.Response) (io.ReadCloser, error) {
+	var (
+		r   io.ReadCloser
+		err error
+	)
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(resp.Body)
+	case "deflate":
+		r, err = zlib.NewReader(resp.Body)
+	default:
+		return nil, fmt.Errorf("mind: unsupported response encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mind: decode response: %w", err)
+	}
+	return decodedBody{Reader: r, decoder: r, body: resp.Body}, nil
+}
+
+// decodedBody reads through a decompressor and closes it and the
+// underlying response body together.
+type decodedBody struct {
+	io.Reader
+	decoder io.Closer
+	body    io.Closer
+}
+
+func (b decodedBody) Close() error {
+	b.decoder.Close()
+	return b.body.Close()
+}
+
+// doGet sends a GET request with client and returns the full response body.
+func doGet(ctx context.Context, client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("mind: create request: %w", err)
+	}
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("mind: request: %w", err)
 	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("mind: read response: %w", err)
+	}
 	if resp.StatusCode >= 400 {
-		errBody, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		return nil, fmt.Errorf("mind: server returned %d: %s", resp.StatusCode, string(errBody))
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
-	return resp.Body, nil
+	return body, nil
 }
 
 // ─── Groq client ─────────────────────────────────────────────────────────────
 
 type groqClient struct {
-	apiKey string
-	model  string
+	http       *http.Client
+	maxBody    int64 // see core.Config.MaxRequestBytes
+	apiKey     string
+	model      string
+	systemRole string // "system" or "developer"; see core.Config.SystemRole
+	stop       []string
+	seed       *int64
+	format     ResponseFormat
 }
 
 type groqRequest struct {
-	Model    string        `json:"model"`
-	Messages []chatMessage `json:"messages"`
-	Stream   bool          `json:"stream"`
+	Model          string                `json:"model"`
+	Messages       []chatMessage         `json:"messages"`
+	Stream         bool                  `json:"stream"`
+	StreamOptions  streamOptions         `json:"stream_options"`
+	Stop           []string              `json:"stop,omitempty"`
+	Seed           *int64                `json:"seed,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Tools          []openAITool          `json:"tools,omitempty"`
+}
+
+// streamOptions asks OpenAI-style APIs to append a usage-only chunk.
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIUsage is the token usage object of OpenAI-style APIs.
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
 type groqDelta struct {
 	Choices []struct {
 		Delta struct {
-			Content string `json:"content"`
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
 		} `json:"delta"`
 		FinishReason *string `json:"finish_reason"`
 	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+	XGroq *struct {
+		Usage *openAIUsage `json:"usage"`
+	} `json:"x_groq"`
+}
+
+func (c *groqClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
+	body, size, err := c.post(ctx, system, user, nil)
+	if err != nil {
+		return nil, err
+	}
+	stream, ch := newStream("groq", c.model)
+	stream.requestBytes = size
+	go sseStream(ctx, body, stream, ch, openAIDelta(stream))
+	return stream, nil
+}
+
+func (c *groqClient) StreamEvents(ctx context.Context, system, user string, tools []Tool) (*EventStream, error) {
+	body, size, err := c.post(ctx, system, user, tools)
+	if err != nil {
+		return nil, err
+	}
+	events, stream, ch := newEventStream("groq", c.model)
+	stream.requestBytes = size
+	go sseStream(ctx, body, stream, ch, openAIEvents(stream))
+	return events, nil
 }
 
-func (c *groqClient) Stream(ctx context.Context, system, user string) (<-chan string, error) {
+// post sends the chat completion request and returns the streaming
+// response body and the size of the request.
+func (c *groqClient) post(ctx context.Context, system, user string, tools []Tool) (io.ReadCloser, int, error) {
 	payload := groqRequest{
 		Model: c.model,
 		Messages: []chatMessage{
-			{Role: "system", Content: system},
+			{Role: c.systemRole, Content: system},
 			{Role: "user", Content: user},
 		},
-		Stream: true,
+		Stream:         true,
+		StreamOptions:  streamOptions{IncludeUsage: true},
+		Stop:           c.stop,
+		Seed:           c.seed,
+		ResponseFormat: c.format.openAI(),
+		Tools:          openAITools(tools),
 	}
 
-	body, err := doPost(ctx, "https://api.groq.com/openai/v1/chat/completions",
+	body, size, err := doPost(ctx, c.http, "https://api.groq.com/openai/v1/chat/completions",
 		map[string]string{"Authorization": "Bearer " + c.apiKey},
-		payload,
+		payload, c.maxBody,
 	)
 	if err != nil {
-		return nil, err
+		return nil, size, formatRejected("groq", c.model, c.format, err)
 	}
+	return body, size, nil
+}
 
-	ch := make(chan string, 64)
-	go sseStream(ctx, body, ch, func(data []byte) (string, bool, error) {
-		var msg groqDelta
-		if err := json.Unmarshal(data, &msg); err != nil {
-			return "", false, err
-		}
-		if len(msg.Choices) == 0 {
-			return "", false, nil
+// openAIDelta returns the sseStream delta extractor for OpenAI-style chat
+// completion chunks, recording usage and the finish reason on stream.
+func openAIDelta(stream *Stream) func([]byte) ([]string, bool, error) {
+	return func(data []byte) ([]string, bool, error) {
+		msg, err := openAIChunk(stream, data)
+		if err != nil || len(msg.Choices) == 0 || msg.Choices[0].Delta.Content == "" {
+			return nil, false, err
 		}
-		choice := msg.Choices[0]
-		if choice.FinishReason != nil && *choice.FinishReason == "stop" {
-			return "", true, nil
+		return []string{msg.Choices[0].Delta.Content}, false, nil
+	}
+}
+
+// openAIChunk decodes an OpenAI-style chat completion chunk and records
+// its usage and finish reason on stream.
+func openAIChunk(stream *Stream, data []byte) (groqDelta, error) {
+	var msg groqDelta
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, err
+	}
+	// The usage chunk arrives after the finish_reason chunk, so keep
+	// reading until [DONE] rather than stopping at "stop".
+	if msg.Usage != nil {
+		stream.usage = Usage{InputTokens: msg.Usage.PromptTokens, OutputTokens: msg.Usage.CompletionTokens}
+	} else if msg.XGroq != nil && msg.XGroq.Usage != nil {
+		stream.usage = Usage{InputTokens: msg.XGroq.Usage.PromptTokens, OutputTokens: msg.XGroq.Usage.CompletionTokens}
+	}
+	if len(msg.Choices) > 0 {
+		if reason := msg.Choices[0].FinishReason; reason != nil {
+			stream.truncated = *reason == "length"
+			stream.filtered = *reason == "content_filter"
 		}
-		return choice.Delta.Content, false, nil
-	})
-	return ch, nil
+	}
+	return msg, nil
 }
 
 // ─── Ollama client ────────────────────────────────────────────────────────────
 
 type ollamaClient struct {
-	host  string
-	model string
+	http    *http.Client
+	maxBody int64 // see core.Config.MaxRequestBytes
+	host    string
+	model   string
+	stop    []string
+	seed    *int64
+	format  ResponseFormat
 }
 
 type ollamaRequest struct {
-	Model    string        `json:"model"`
-	Messages []chatMessage `json:"messages"`
-	Stream   bool          `json:"stream"`
+	Model    string          `json:"model"`
+	Messages []chatMessage   `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   json.RawMessage `json:"format,omitempty"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+// ollamaOptions holds the model parameters Ollama takes under "options".
+type ollamaOptions struct {
+	Stop []string `json:"stop,omitempty"`
+	Seed *int64   `json:"seed,omitempty"`
 }
 
 type ollamaDelta struct {
@@ -210,9 +581,14 @@ type ollamaDelta struct {
 		Content string `json:"content"`
 	} `json:"message"`
 	Done bool `json:"done"`
+	// DoneReason is set on the final message: "stop" when the answer is
+	// complete, "length" when num_predict or the context window cut it off.
+	DoneReason      string `json:"done_reason"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
-func (c *ollamaClient) Stream(ctx context.Context, system, user string) (<-chan string, error) {
+func (c *ollamaClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
 	host := c.host
 	if host == "" {
 		host = "http://localhost:11434"
@@ -226,23 +602,29 @@ func (c *ollamaClient) Stream(ctx context.Context, system, user string) (<-chan
 			{Role: "user", Content: user},
 		},
 		Stream: true,
+		Format: c.format.ollama(),
+	}
+	if len(c.stop) > 0 || c.seed != nil {
+		payload.Options = &ollamaOptions{Stop: c.stop, Seed: c.seed}
 	}
 
-	body, err := doPost(ctx, url, nil, payload)
+	body, size, err := doPost(ctx, c.http, url, nil, payload, c.maxBody)
 	if err != nil {
-		return nil, err
+		return nil, formatRejected("ollama", c.model, c.format, err)
 	}
 
 	// Ollama streams newline-delimited JSON, not SSE.
-	ch := make(chan string, 64)
+	stream, ch := newStream("ollama", c.model)
+	stream.requestBytes = size
 	go func() {
 		defer close(ch)
 		defer body.Close()
 
-		scanner := bufio.NewScanner(body)
+		scanner := newLineScanner(body)
 		for scanner.Scan() {
 			select {
 			case <-ctx.Done():
+				stream.Fail(ctx, ctx.Err())
 				return
 			default:
 			}
@@ -254,13 +636,17 @@ func (c *ollamaClient) Stream(ctx context.Context, system, user string) (<-chan
 				select {
 				case ch <- msg.Message.Content:
 				case <-ctx.Done():
+					stream.Fail(ctx, ctx.Err())
 					return
 				}
 			}
 			if msg.Done {
+				stream.usage = Usage{InputTokens: msg.PromptEvalCount, OutputTokens: msg.EvalCount}
+				stream.truncated = msg.DoneReason == "length"
 				return
 			}
 		}
+		stream.Fail(ctx, scanner.Err())
 	}()
-	return ch, nil
+	return stream, nil
 }
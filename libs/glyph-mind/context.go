@@ -0,0 +1,51 @@
+package mind
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ollamaContextWindow is Ollama's default num_ctx. Ollama silently truncates
+// longer prompts unless the model is run with a larger num_ctx, so the
+// server default matters more than what the model itself supports.
+const ollamaContextWindow = 4096
+
+// contextWindows maps model name prefixes to context sizes in tokens. The
+// first matching prefix wins, so longer prefixes come first.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"claude-", 200_000},
+	{"llama-3.1-", 131_072},
+	{"llama-3.3-", 131_072},
+	{"llama3-", 8_192},
+	{"meta-llama/llama-4", 131_072},
+	{"openai/gpt-oss", 131_072},
+	{"moonshotai/kimi-k2", 131_072},
+	{"deepseek-r1-distill", 131_072},
+	{"qwen", 32_768},
+	{"mixtral-", 32_768},
+	{"gemma2", 8_192},
+}
+
+// EstimateTokens approximates the token count of s with the common
+// four-characters-per-token heuristic.
+func EstimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + 3) / 4
+}
+
+// ContextWindow returns the context size in tokens for model on provider,
+// or 0 when it is unknown.
+func ContextWindow(provider, model string) int {
+	if normalizeProvider(provider) == "ollama" {
+		return ollamaContextWindow
+	}
+	model = strings.ToLower(model)
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			return w.tokens
+		}
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package mind
+
+import (
+	"errors"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// defaultModels is the model each provider uses when neither ai_model nor
+// the [models] table names one.
+var defaultModels = map[string]string{
+	"groq":   "llama-3.3-70b-versatile",
+	"ollama": "llama3.2",
+	"claude": "claude-sonnet-4-6",
+}
+
+// DefaultModel returns the built-in default model for provider, or "" for
+// an unknown provider.
+func DefaultModel(provider string) string {
+	return defaultModels[normalizeProvider(provider)]
+}
+
+// ResolveModel returns the model a client for cfg should request. ai_model
+// wins unless it is empty or clearly belongs to another provider; otherwise
+// the [models] table entry for the provider is used, then the built-in
+// default.
+//
+// For azure the deployment decides the model, so it is the deployment name
+// unless ai_model is set.
+func ResolveModel(cfg core.Config) string {
+	provider := normalizeProvider(cfg.AIProvider)
+	if provider == "azure" && cfg.AIModel == "" {
+		return cfg.Azure.Deployment
+	}
+	if cfg.AIModel != "" && !mismatchedModel(provider, cfg.AIModel) {
+		return cfg.AIModel
+	}
+	for name, model := range cfg.Models {
+		if strings.EqualFold(name, provider) && model != "" {
+			return model
+		}
+	}
+	return defaultModels[provider]
+}
+
+// mismatchedModel reports whether model clearly belongs to a provider
+// other than provider. Unrecognized names are not considered mismatched.
+func mismatchedModel(provider, model string) bool {
+	err := CheckModel(provider, model)
+	return err != nil && !errors.Is(err, ErrUnrecognizedModel)
+}
+
+// normalizeProvider lower-cases provider and maps "" to groq, matching
+// NewClientFromConfig.
+func normalizeProvider(provider string) string {
+	provider = strings.ToLower(provider)
+	if provider == "" {
+		return "groq"
+	}
+	return provider
+}
@@ -0,0 +1,110 @@
+package mind
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+const plainSSE = "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n" +
+	"data: {\"choices\":[{\"delta\":{\"content\":\", world\"}}]}\n\n" +
+	"data: [DONE]\n\n"
+
+// compressed returns s encoded as encoding: "gzip", "deflate" or "" for
+// none.
+func compressed(t *testing.T, encoding, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "":
+		return []byte(s)
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown encoding %q", encoding)
+	}
+	io.WriteString(w, s)
+	w.Close()
+	return buf.Bytes()
+}
+
+// serveEncoded starts a test server that replies with status and body,
+// compressed with encoding, which Content-Encoding names.
+func serveEncoded(t *testing.T, status int, encoding, header, body string) *httptest.Server {
+	t.Helper()
+	data := compressed(t, encoding, body)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "gzip, deflate" {
+			t.Errorf("Accept-Encoding = %q, want %q", got, "gzip, deflate")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		if header != "" {
+			w.Header().Set("Content-Encoding", header)
+		}
+		w.WriteHeader(status)
+		// Send it in pieces, as a stream arrives.
+		for len(data) > 0 {
+			n := min(len(data), 16)
+			w.Write(data[:n])
+			w.(http.Flusher).Flush()
+			data = data[n:]
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEncodedStream(t *testing.T) {
+	tests := []struct {
+		name, encoding, header string
+	}{
+		{"identity", "", ""},
+		{"gzip", "gzip", "gzip"},
+		{"x-gzip", "gzip", "x-gzip"},
+		{"deflate", "deflate", "deflate"},
+		{"header case and space", "gzip", " GZIP "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serveEncoded(t, http.StatusOK, tt.encoding, tt.header, plainSSE)
+			stream, err := testClient(t, core.Config{AIProvider: "groq"}, srv).Stream(t.Context(), "system", "user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := drain(stream); got != "Hello, world" {
+				t.Errorf("answer = %q, want %q", got, "Hello, world")
+			}
+			if err := stream.Err(); err != nil {
+				t.Errorf("Err = %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodedErrorBody(t *testing.T) {
+	srv := serveEncoded(t, http.StatusUnauthorized, "gzip", "gzip", `{"error":"invalid api key"}`)
+	_, err := testClient(t, core.Config{AIProvider: "groq"}, srv).Stream(t.Context(), "system", "user")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !strings.Contains(httpErr.Body, "invalid api key") {
+		t.Errorf("err = %v, want an *HTTPError with the decoded body", err)
+	}
+}
+
+func TestUnsupportedEncoding(t *testing.T) {
+	srv := serveEncoded(t, http.StatusOK, "", "br", plainSSE)
+	_, err := testClient(t, core.Config{AIProvider: "groq"}, srv).Stream(t.Context(), "system", "user")
+	if err == nil || !strings.Contains(err.Error(), `unsupported response encoding "br"`) {
+		t.Errorf("err = %v, want an unsupported encoding error", err)
+	}
+}
@@ -0,0 +1,257 @@
+package mind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// EventClient is implemented by clients that can stream a response as
+// structured events instead of plain text, for callers that offer the
+// model tools and need to see its tool calls. Claude, Groq and Azure
+// clients implement it.
+type EventClient interface {
+	// StreamEvents sends a prompt with the tools the model may call and
+	// returns the in-flight response. As with Client.Stream, the caller
+	// must drain C, and errors after it is opened are reported by Err.
+	StreamEvents(ctx context.Context, system, user string, tools []Tool) (*EventStream, error)
+}
+
+// Tool describes a function the model may call.
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the JSON Schema of the call's arguments, an object
+	// schema. Nil means the tool takes no arguments.
+	Parameters json.RawMessage
+}
+
+// EventKind tells which field of an Event is set.
+type EventKind int
+
+const (
+	// EventText carries a text delta in Text.
+	EventText EventKind = iota + 1
+	// EventToolCall carries a fragment of a tool call in ToolCall.
+	EventToolCall
+	// EventFinish reports why the response ended in FinishReason. It is
+	// the last event, unless the stream fails first.
+	EventFinish
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventText:
+		return "text"
+	case EventToolCall:
+		return "tool_call"
+	case EventFinish:
+		return "finish"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Finish reasons, as reported by EventFinish events whatever the
+// provider. A reason a provider adds later is passed through as it is.
+const (
+	FinishStop      = "stop"       // the answer is complete
+	FinishLength    = "length"     // the output token limit cut it off
+	FinishToolCalls = "tool_calls" // the model is waiting for tool results
+	FinishFiltered  = "filtered"   // the provider withheld the response
+)
+
+// Event is one item of an EventStream; Kind tells which field is set.
+type Event struct {
+	Kind         EventKind
+	Text         string
+	ToolCall     ToolCallDelta
+	FinishReason string
+}
+
+// ToolCallDelta is a fragment of a tool call. The model streams the
+// arguments of a call in pieces: the first fragment of a call carries its
+// ID and Name, and the Arguments of all its fragments, concatenated, form
+// the JSON arguments object.
+type ToolCallDelta struct {
+	// Index identifies the call within the response, so that fragments
+	// of calls the model makes in parallel can be told apart. Indexes are
+	// not necessarily contiguous.
+	Index     int
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// EventStream is an in-flight response delivered as events. Read them
+// from C until it is closed; the accessor methods, which mean the same as
+// Stream's, are valid once C has been closed.
+type EventStream struct {
+	// C delivers events and is closed when the response ends.
+	C <-chan Event
+
+	info *Stream // records the provider, model, usage and error
+}
+
+// newEventStream returns an EventStream for model of provider, the Stream
+// its producer records the response details on, and the channel it writes
+// events to.
+func newEventStream(provider, model string) (*EventStream, *Stream, chan Event) {
+	ch := make(chan Event, 64)
+	info, _ := newStream(provider, model)
+	return &EventStream{C: ch, info: info}, info, ch
+}
+
+func (s *EventStream) Provider() string  { return s.info.Provider() }
+func (s *EventStream) Model() string     { return s.info.Model() }
+func (s *EventStream) RequestBytes() int { return s.info.RequestBytes() }
+func (s *EventStream) Usage() Usage      { return s.info.Usage() }
+func (s *EventStream) Truncated() bool   { return s.info.Truncated() }
+func (s *EventStream) Filtered() bool    { return s.info.Filtered() }
+func (s *EventStream) Err() error        { return s.info.Err() }
+
+// NewEventClientFromConfig constructs the EventClient for cfg.AIProvider.
+// It is checked like NewClientFromConfig, but fallbacks are not used:
+// tool calls are specific to the provider that made them. Providers that
+// cannot stream events, such as Ollama, return an
+// *UnsupportedFeatureError.
+func NewEventClientFromConfig(cfg core.Config) (EventClient, error) {
+	client, err := newProviderClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ec, ok := client.(EventClient)
+	if !ok {
+		return nil, &UnsupportedFeatureError{Provider: normalizeProvider(cfg.AIProvider), Feature: "streaming events with tool calls"}
+	}
+	return ec, nil
+}
+
+// sendEvents sends events to ch, reporting false when ctx is done first.
+func sendEvents[T any](ctx context.Context, ch chan<- T, events []T) bool {
+	for _, e := range events {
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// ─── OpenAI-style tools ──────────────────────────────────────────────────────
+
+// openAITool is a tool in the "tools" list of an OpenAI-style request.
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+func openAITools(tools []Tool) []openAITool {
+	var out []openAITool
+	for _, t := range tools {
+		tool := openAITool{Type: "function"}
+		tool.Function.Name = t.Name
+		tool.Function.Description = t.Description
+		tool.Function.Parameters = t.Parameters
+		out = append(out, tool)
+	}
+	return out
+}
+
+// openAIEvents returns the sseStream extractor that turns OpenAI-style
+// chat completion chunks into events, recording usage and the finish
+// reason on stream.
+func openAIEvents(stream *Stream) func([]byte) ([]Event, bool, error) {
+	return func(data []byte) ([]Event, bool, error) {
+		msg, err := openAIChunk(stream, data)
+		if err != nil || len(msg.Choices) == 0 {
+			return nil, false, err
+		}
+		choice := msg.Choices[0]
+		var events []Event
+		if choice.Delta.Content != "" {
+			events = append(events, Event{Kind: EventText, Text: choice.Delta.Content})
+		}
+		for _, call := range choice.Delta.ToolCalls {
+			events = append(events, Event{Kind: EventToolCall, ToolCall: ToolCallDelta{
+				Index:     call.Index,
+				ID:        call.ID,
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			}})
+		}
+		if choice.FinishReason != nil {
+			events = append(events, Event{Kind: EventFinish, FinishReason: openAIFinishReason(*choice.FinishReason)})
+		}
+		return events, false, nil
+	}
+}
+
+// openAIFinishReason maps an OpenAI-style finish_reason to the Finish
+// constants.
+func openAIFinishReason(reason string) string {
+	switch reason {
+	case "tool_calls", "function_call":
+		return FinishToolCalls
+	case "content_filter":
+		return FinishFiltered
+	}
+	return reason
+}
+
+// ─── Claude tools ────────────────────────────────────────────────────────────
+
+// claudeTool is a tool in the "tools" list of a Claude request.
+type claudeTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// emptyObjectSchema is the input schema of a tool without parameters;
+// Claude requires one.
+var emptyObjectSchema = json.RawMessage(`{"type":"object","properties":{}}`)
+
+func claudeTools(tools []Tool) []claudeTool {
+	var out []claudeTool
+	for _, t := range tools {
+		schema := t.Parameters
+		if len(schema) == 0 {
+			schema = emptyObjectSchema
+		}
+		out = append(out, claudeTool{Name: t.Name, Description: t.Description, InputSchema: schema})
+	}
+	return out
+}
+
+// claudeContentBlockStart is the payload of a content_block_start event,
+// which opens a text or tool_use block.
+type claudeContentBlockStart struct {
+	Index        int `json:"index"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+}
+
+// claudeFinishReason maps a Claude stop_reason to the Finish constants.
+func claudeFinishReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return FinishStop
+	case "max_tokens":
+		return FinishLength
+	case "tool_use":
+		return FinishToolCalls
+	case "refusal":
+		return FinishFiltered
+	}
+	return reason
+}
@@ -0,0 +1,228 @@
+package mind
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+var weatherTool = Tool{
+	Name:        "get_weather",
+	Description: "Current weather of a city",
+	Parameters:  json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`),
+}
+
+// streamEvents sends a prompt with tools to a test server replying with
+// reply, and returns the decoded request body, the events received and
+// the drained stream.
+func streamEvents(t *testing.T, cfg core.Config, tools []Tool, reply string) (map[string]any, []Event, *EventStream) {
+	t.Helper()
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies <- b
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+
+	ec, ok := testClient(t, cfg, srv).(EventClient)
+	if !ok {
+		t.Fatalf("%s client is not an EventClient", cfg.AIProvider)
+	}
+	es, err := ec.StreamEvents(t.Context(), "system", "user", tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []Event
+	for e := range es.C {
+		events = append(events, e)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatal(err)
+	}
+	return payload, events, es
+}
+
+// claudeToolStream is a Claude response that says something, then calls
+// get_weather with its arguments split over two deltas.
+const claudeToolStream = `event: message_start
+data: {"type":"message_start","message":{"usage":{"input_tokens":12,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Checking."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_01","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\": "}}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Lima\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":30}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+
+func TestClaudeStreamEvents(t *testing.T) {
+	payload, events, es := streamEvents(t, core.Config{AIProvider: "claude"}, []Tool{weatherTool, {Name: "now"}}, claudeToolStream)
+
+	want := []Event{
+		{Kind: EventText, Text: "Checking."},
+		{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 1, ID: "toolu_01", Name: "get_weather"}},
+		{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 1, Arguments: `{"city": `}},
+		{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 1, Arguments: `"Lima"}`}},
+		{Kind: EventFinish, FinishReason: FinishToolCalls},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events =\n%+v\nwant\n%+v", events, want)
+	}
+	if err := es.Err(); err != nil {
+		t.Errorf("Err = %v", err)
+	}
+	if got := es.Usage(); got != (Usage{InputTokens: 12, OutputTokens: 30}) {
+		t.Errorf("usage = %+v", got)
+	}
+	if es.Provider() != "claude" {
+		t.Errorf("provider = %q", es.Provider())
+	}
+
+	wantTools := []any{
+		map[string]any{
+			"name":         "get_weather",
+			"description":  "Current weather of a city",
+			"input_schema": map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}, "required": []any{"city"}},
+		},
+		map[string]any{"name": "now", "input_schema": map[string]any{"type": "object", "properties": map[string]any{}}},
+	}
+	if !reflect.DeepEqual(payload["tools"], wantTools) {
+		t.Errorf("tools = %#v, want %#v", payload["tools"], wantTools)
+	}
+}
+
+// openAIToolStream is an OpenAI-style response that calls two tools in
+// parallel, interleaving the fragments of their arguments, and ends with
+// a usage chunk.
+const openAIToolStream = `data: {"choices":[{"delta":{"content":"Checking both."}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":\"Lima\"}"}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":1,"function":{"arguments":"{\"city\":\"Oslo\"}"}}]}}]}
+
+data: {"choices":[{"delta":{},"finish_reason":"tool_calls"}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":20,"completion_tokens":9}}
+
+data: [DONE]
+
+`
+
+func TestOpenAIStreamEvents(t *testing.T) {
+	for _, provider := range []string{"groq", "azure"} {
+		t.Run(provider, func(t *testing.T) {
+			payload, events, es := streamEvents(t, core.Config{AIProvider: provider}, []Tool{weatherTool}, openAIToolStream)
+
+			want := []Event{
+				{Kind: EventText, Text: "Checking both."},
+				{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 0, ID: "call_a", Name: "get_weather"}},
+				{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 1, ID: "call_b", Name: "get_weather"}},
+				{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 0, Arguments: `{"city":"Lima"}`}},
+				{Kind: EventToolCall, ToolCall: ToolCallDelta{Index: 1, Arguments: `{"city":"Oslo"}`}},
+				{Kind: EventFinish, FinishReason: FinishToolCalls},
+			}
+			if !reflect.DeepEqual(events, want) {
+				t.Errorf("events =\n%+v\nwant\n%+v", events, want)
+			}
+			if err := es.Err(); err != nil {
+				t.Errorf("Err = %v", err)
+			}
+			if got := es.Usage(); got != (Usage{InputTokens: 20, OutputTokens: 9}) {
+				t.Errorf("usage = %+v", got)
+			}
+
+			wantTools := []any{map[string]any{
+				"type": "function",
+				"function": map[string]any{
+					"name":        "get_weather",
+					"description": "Current weather of a city",
+					"parameters":  map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}, "required": []any{"city"}},
+				},
+			}}
+			if !reflect.DeepEqual(payload["tools"], wantTools) {
+				t.Errorf("tools = %#v, want %#v", payload["tools"], wantTools)
+			}
+		})
+	}
+}
+
+func TestStreamWithoutToolsOmitsThem(t *testing.T) {
+	for _, provider := range []string{"groq", "azure", "claude"} {
+		if _, ok := lookup(capturePayload(t, core.Config{AIProvider: provider}), "tools"); ok {
+			t.Errorf("%s request without tools has a tools field", provider)
+		}
+	}
+}
+
+func TestFinishReasons(t *testing.T) {
+	openAI := map[string]string{"stop": FinishStop, "length": FinishLength, "tool_calls": FinishToolCalls, "function_call": FinishToolCalls, "content_filter": FinishFiltered, "new_reason": "new_reason"}
+	for in, want := range openAI {
+		if got := openAIFinishReason(in); got != want {
+			t.Errorf("openAIFinishReason(%q) = %q, want %q", in, got, want)
+		}
+	}
+	claude := map[string]string{"end_turn": FinishStop, "stop_sequence": FinishStop, "max_tokens": FinishLength, "tool_use": FinishToolCalls, "refusal": FinishFiltered, "pause_turn": "pause_turn"}
+	for in, want := range claude {
+		if got := claudeFinishReason(in); got != want {
+			t.Errorf("claudeFinishReason(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNewEventClientFromConfig(t *testing.T) {
+	_, err := NewEventClientFromConfig(core.Config{AIProvider: "ollama"})
+	var unsupported *UnsupportedFeatureError
+	if !errors.As(err, &unsupported) || unsupported.Provider != "ollama" {
+		t.Errorf("ollama: err = %v, want *UnsupportedFeatureError", err)
+	}
+	for _, provider := range []string{"groq", "claude"} {
+		if _, err := NewEventClientFromConfig(core.Config{AIProvider: provider, APIKey: "k"}); err != nil {
+			t.Errorf("%s: %v", provider, err)
+		}
+	}
+}
+
+func TestEventKindString(t *testing.T) {
+	for kind, want := range map[EventKind]string{EventText: "text", EventToolCall: "tool_call", EventFinish: "finish", 0: "EventKind(0)"} {
+		if got := kind.String(); got != want {
+			t.Errorf("EventKind(%d).String() = %q, want %q", int(kind), got, want)
+		}
+	}
+}
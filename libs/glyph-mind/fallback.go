@@ -0,0 +1,62 @@
+package mind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// fallbackClient tries its clients in order until one starts answering. A
+// client counts as failed if Stream returns an error or its stream ends
+// with an error before the first chunk. Failures after the first chunk are
+// not retried, so no partial answer is ever followed by another one.
+type fallbackClient struct {
+	clients []Client
+	labels  []string // "provider" or "provider:model", for error messages
+}
+
+func (c *fallbackClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
+	var errs []error
+	for i, client := range c.clients {
+		stream, err := client.Stream(ctx, system, user)
+		if err == nil {
+			stream, err = awaitFirstChunk(stream)
+		}
+		if err == nil {
+			return stream, nil
+		}
+		if ctx.Err() != nil {
+			// Canceled or timed out: another provider would fare no better.
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", c.labels[i], err))
+	}
+	return nil, &core.AppError{Msg: "every provider failed", Err: errors.Join(errs...)}
+}
+
+// awaitFirstChunk waits until s delivers its first chunk or ends. It
+// returns s's error if s ended with one before any text, and otherwise a
+// Stream that replays the first chunk and forwards the rest of s.
+func awaitFirstChunk(s *Stream) (*Stream, error) {
+	first, ok := <-s.C
+	if !ok {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	out, ch := newStream(s.provider, s.model)
+	out.start, out.requestBytes = s.start, s.requestBytes
+	go func() {
+		ch <- first
+		for chunk := range s.C {
+			ch <- chunk
+		}
+		out.usage, out.truncated, out.filtered, out.err = s.usage, s.truncated, s.filtered, s.err
+		close(ch)
+	}()
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package mind
+
+import (
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// clientKey returns the API key c sends, or "" for a client without one.
+func clientKey(c Client) string {
+	switch c := c.(type) {
+	case *groqClient:
+		return c.apiKey
+	case *claudeClient:
+		return c.apiKey
+	case *azureClient:
+		return c.apiKey
+	}
+	return ""
+}
+
+func TestFallbackAPIKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      core.Config
+		wantKeys []string // of the primary, then each fallback
+		wantErr  string
+	}{
+		{
+			name:    "other vendor without its own key",
+			cfg:     core.Config{AIProvider: "groq", APIKey: "gsk", Fallbacks: []string{"claude"}},
+			wantErr: `fallback "claude": no API key for claude; set api_keys.claude`,
+		},
+		{
+			name:     "other vendor with api_keys",
+			cfg:      core.Config{AIProvider: "groq", APIKey: "gsk", APIKeys: map[string]string{"claude": "sk-ant"}, Fallbacks: []string{"claude:claude-haiku-4-5"}},
+			wantKeys: []string{"gsk", "sk-ant"},
+		},
+		{
+			name:     "api_keys is matched case-insensitively",
+			cfg:      core.Config{AIProvider: "groq", APIKey: "gsk", APIKeys: map[string]string{"Claude": "sk-ant"}, Fallbacks: []string{"CLAUDE"}},
+			wantKeys: []string{"gsk", "sk-ant"},
+		},
+		{
+			name:     "same provider shares api_key",
+			cfg:      core.Config{AIProvider: "groq", APIKey: "gsk", Fallbacks: []string{"groq:llama-3.1-8b-instant"}},
+			wantKeys: []string{"gsk", "gsk"},
+		},
+		{
+			name:     "empty provider is groq",
+			cfg:      core.Config{APIKey: "gsk", Fallbacks: []string{"groq"}},
+			wantKeys: []string{"gsk", "gsk"},
+		},
+		{
+			name:     "ollama needs no key",
+			cfg:      core.Config{AIProvider: "claude", APIKey: "sk-ant", Fallbacks: []string{"ollama:llama3.2"}},
+			wantKeys: []string{"sk-ant", ""},
+		},
+		{
+			name:     "ollama primary with keyed fallback",
+			cfg:      core.Config{AIProvider: "ollama", APIKey: "stray", APIKeys: map[string]string{"groq": "gsk"}, Fallbacks: []string{"groq"}},
+			wantKeys: []string{"", "gsk"},
+		},
+		{
+			name:     "primary key from api_keys",
+			cfg:      core.Config{AIProvider: "claude", APIKeys: map[string]string{"claude": "sk-ant"}},
+			wantKeys: []string{"sk-ant"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newFallbackClient(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			clients := []Client{client}
+			if fc, ok := client.(*fallbackClient); ok {
+				clients = fc.clients
+			}
+			if len(clients) != len(tt.wantKeys) {
+				t.Fatalf("got %d clients, want %d", len(clients), len(tt.wantKeys))
+			}
+			for i, c := range clients {
+				if got := clientKey(c); got != tt.wantKeys[i] {
+					t.Errorf("client %d key = %q, want %q", i, got, tt.wantKeys[i])
+				}
+			}
+		})
+	}
+}
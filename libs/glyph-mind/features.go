@@ -0,0 +1,101 @@
+package mind
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// maxOpenAIStop is the number of stop sequences OpenAI-style APIs (groq,
+// azure) accept; longer lists are rejected with a 400.
+const maxOpenAIStop = 4
+
+// UnsupportedFeatureError reports a setting a provider cannot honor, such
+// as JSON mode on an Ollama model without it. Clients return it instead of
+// sending a request that fails obscurely or quietly dropping the setting;
+// UnsupportedFeatures lists the settings a provider ignores, for warnings.
+type UnsupportedFeatureError struct {
+	Provider string
+	Feature  string // what was asked for, such as "JSON mode"
+	Err      error  // the provider's own error, if it reported one
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	msg := fmt.Sprintf("%s does not support %s", e.Provider, e.Feature)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *UnsupportedFeatureError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedFeatures returns an error for each setting of cfg that its
+// provider, or one of its fallbacks, accepts but ignores, such as
+// system_role with Claude. The client still works; callers print these as
+// warnings. Settings a provider rejects outright make NewClientFromConfig
+// fail instead.
+func UnsupportedFeatures(cfg core.Config) []*UnsupportedFeatureError {
+	providers := []string{normalizeProvider(cfg.AIProvider)}
+	for _, spec := range cfg.Fallbacks {
+		name, _, _ := strings.Cut(spec, ":")
+		providers = append(providers, normalizeProvider(name))
+	}
+
+	var unsupported []*UnsupportedFeatureError
+	seen := map[string]bool{}
+	for _, provider := range providers {
+		if seen[provider] {
+			continue
+		}
+		seen[provider] = true
+		switch provider {
+		case "ollama", "claude":
+			if strings.EqualFold(cfg.SystemRole, "developer") {
+				unsupported = append(unsupported, &UnsupportedFeatureError{
+					Provider: provider,
+					Feature:  `system_role = "developer"; instructions are sent as the system prompt`,
+				})
+			}
+		}
+		if provider == "claude" && cfg.Seed != nil {
+			unsupported = append(unsupported, &UnsupportedFeatureError{
+				Provider: provider,
+				Feature:  "seed; answers are sampled without one",
+			})
+		}
+	}
+	return unsupported
+}
+
+// checkStop returns an *UnsupportedFeatureError when provider, an
+// OpenAI-style API, would reject stop for being too long.
+func checkStop(provider string, stop []string) error {
+	if len(stop) > maxOpenAIStop {
+		return &UnsupportedFeatureError{
+			Provider: provider,
+			Feature:  fmt.Sprintf("more than %d stop sequences (got %d)", maxOpenAIStop, len(stop)),
+		}
+	}
+	return nil
+}
+
+// formatRejected wraps err, a failed request made with format, in an
+// *UnsupportedFeatureError when the provider rejected the request as bad:
+// with a JSON format set, that is the likely cause.
+func formatRejected(provider, model string, format ResponseFormat, err error) error {
+	var httpErr *HTTPError
+	if !format.JSON || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+	feature := "JSON mode"
+	if format.Schema != nil {
+		feature = "json_schema"
+	}
+	return &UnsupportedFeatureError{Provider: provider, Feature: feature + " with model " + model, Err: err}
+}
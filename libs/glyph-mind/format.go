@@ -0,0 +1,127 @@
+package mind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// ResponseFormat describes the structured output requested from a provider.
+// The zero value asks for free text.
+type ResponseFormat struct {
+	// JSON asks for a single JSON object.
+	JSON bool
+	// Schema, when set, is a JSON schema the object must follow.
+	Schema json.RawMessage
+}
+
+// responseFormat builds the ResponseFormat for cfg: response_format must be
+// "", "text" or "json", and json_schema names a schema file, which implies
+// "json".
+func responseFormat(cfg core.Config) (ResponseFormat, error) {
+	var rf ResponseFormat
+	switch strings.ToLower(cfg.ResponseFormat) {
+	case "", "text":
+	case "json":
+		rf.JSON = true
+	default:
+		return rf, &core.AppError{Msg: fmt.Sprintf("unknown response_format %q (valid: text, json)", cfg.ResponseFormat)}
+	}
+	if cfg.JSONSchema == "" {
+		return rf, nil
+	}
+
+	data, err := os.ReadFile(cfg.JSONSchema)
+	if err != nil {
+		return rf, &core.AppError{Msg: "cannot read json_schema " + cfg.JSONSchema, Err: err}
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return rf, &core.AppError{Msg: "json_schema " + cfg.JSONSchema + " is not a JSON object", Err: err}
+	}
+	rf.JSON, rf.Schema = true, json.RawMessage(data)
+	return rf, nil
+}
+
+// openAIResponseFormat is the response_format object of OpenAI-style APIs.
+type openAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// openAI returns rf as an OpenAI-style response_format, or nil for text.
+func (rf ResponseFormat) openAI() *openAIResponseFormat {
+	switch {
+	case rf.Schema != nil:
+		return &openAIResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &openAIJSONSchema{Name: "response", Schema: rf.Schema},
+		}
+	case rf.JSON:
+		return &openAIResponseFormat{Type: "json_object"}
+	}
+	return nil
+}
+
+// ollama returns rf as Ollama's format field: "json" or the schema itself,
+// or nil for text.
+func (rf ResponseFormat) ollama() json.RawMessage {
+	switch {
+	case rf.Schema != nil:
+		return rf.Schema
+	case rf.JSON:
+		return json.RawMessage(`"json"`)
+	}
+	return nil
+}
+
+// instruction returns the system prompt addition for providers without a
+// JSON mode, such as Claude, or "" for text.
+func (rf ResponseFormat) instruction() string {
+	if !rf.JSON {
+		return ""
+	}
+	s := "Respond with a single JSON object and nothing else: no prose, no markdown code fences."
+	if rf.Schema != nil {
+		s += " The object must follow this JSON schema:\n" + string(rf.Schema)
+	}
+	return s
+}
+
+// jsonClient fails responses that are not valid JSON. Chunks are passed
+// through as they arrive; the check runs once the response is complete.
+type jsonClient struct {
+	Client
+}
+
+func (c jsonClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
+	s, err := c.Client.Stream(ctx, system, user)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ch := newStream(s.provider, s.model)
+	out.start, out.requestBytes = s.start, s.requestBytes
+	go func() {
+		var text strings.Builder
+		for chunk := range s.C {
+			text.WriteString(chunk)
+			ch <- chunk
+		}
+		out.usage, out.truncated, out.filtered, out.err = s.usage, s.truncated, s.filtered, s.err
+		if out.err == nil && !json.Valid([]byte(text.String())) {
+			out.err = &core.AppError{Msg: "response is not valid JSON"}
+		}
+		close(ch)
+	}()
+	return out, nil
+}
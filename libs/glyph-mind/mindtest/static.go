@@ -0,0 +1,63 @@
+// Package mindtest provides test doubles for glyph-mind clients, so tools
+// and printers can exercise the streaming pipeline without network access.
+package mindtest
+
+import (
+	"context"
+	"sync"
+
+	mind "github.com/reky0/glyph-mind"
+)
+
+// Call records the prompts passed to a single Stream invocation.
+type Call struct {
+	System string
+	User   string
+}
+
+// StaticClient is a mind.Client that streams a fixed list of chunks.
+type StaticClient struct {
+	chunks []string
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ mind.Client = (*StaticClient)(nil)
+
+// NewStaticClient returns a client that emits chunks, in order, on every
+// call to Stream.
+func NewStaticClient(chunks []string) *StaticClient {
+	return &StaticClient{chunks: chunks}
+}
+
+// Stream records the prompts and emits the configured chunks. The channel
+// is closed after the last chunk, or early if ctx is cancelled, in which
+// case the stream's Err reports the cancellation like a real client.
+func (c *StaticClient) Stream(ctx context.Context, system, user string) (*mind.Stream, error) {
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{System: system, User: user})
+	c.mu.Unlock()
+
+	ch := make(chan string)
+	stream := mind.NewStream(ch)
+	go func() {
+		defer close(ch)
+		for _, chunk := range c.chunks {
+			select {
+			case ch <- chunk:
+			case <-ctx.Done():
+				stream.Fail(ctx, ctx.Err())
+				return
+			}
+		}
+	}()
+	return stream, nil
+}
+
+// Calls returns the prompts received so far, oldest first.
+func (c *StaticClient) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
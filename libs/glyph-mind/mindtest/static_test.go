@@ -0,0 +1,57 @@
+package mindtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStaticClient(t *testing.T) {
+	c := NewStaticClient([]string{"Hello", ", ", "world"})
+	for range 2 {
+		stream, err := c.Stream(t.Context(), "system", "user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got strings.Builder
+		for chunk := range stream.C {
+			got.WriteString(chunk)
+		}
+		if got.String() != "Hello, world" {
+			t.Errorf("answer = %q, want %q", got.String(), "Hello, world")
+		}
+		if err := stream.Err(); err != nil {
+			t.Errorf("Err = %v", err)
+		}
+	}
+	if got, want := c.Calls(), []Call{{"system", "user"}, {"system", "user"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls = %+v, want %+v", got, want)
+	}
+}
+
+func TestStaticClientCanceled(t *testing.T) {
+	chunks := make([]string, 100)
+	for i := range chunks {
+		chunks[i] = fmt.Sprint(i)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	stream, err := NewStaticClient(chunks).Stream(ctx, "system", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-stream.C
+	cancel()
+	n := 1
+	for range stream.C {
+		n++
+	}
+	if n == len(chunks) {
+		t.Error("every chunk arrived after the context was canceled")
+	}
+	if err := stream.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err = %v, want it to wrap context.Canceled", err)
+	}
+}
@@ -0,0 +1,124 @@
+package mind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// ModelInfo describes a model available from a provider. Size and
+// ModifiedAt are zero when the provider does not report them.
+type ModelInfo struct {
+	Name       string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// ModelLister is implemented by clients that can enumerate their models.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+}
+
+// ListModels returns the models available from the configured provider;
+// fallbacks are not consulted.
+func ListModels(ctx context.Context, cfg core.Config) ([]ModelInfo, error) {
+	client, err := newProviderClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := client.(ModelLister)
+	if !ok {
+		return nil, &core.AppError{
+			Msg: fmt.Sprintf("provider %q cannot list models", cfg.AIProvider),
+		}
+	}
+	return lister.ListModels(ctx)
+}
+
+// openAIModelList is the /models response shape shared by OpenAI-style APIs.
+type openAIModelList struct {
+	Data []struct {
+		ID      string `json:"id"`
+		Created int64  `json:"created"`
+	} `json:"data"`
+}
+
+func (c *groqClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	body, err := doGet(ctx, c.http, "https://api.groq.com/openai/v1/models",
+		map[string]string{"Authorization": "Bearer " + c.apiKey},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var list openAIModelList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("mind: decode model list: %w", err)
+	}
+	models := make([]ModelInfo, 0, len(list.Data))
+	for _, m := range list.Data {
+		info := ModelInfo{Name: m.ID}
+		if m.Created > 0 {
+			info.ModifiedAt = time.Unix(m.Created, 0)
+		}
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+type claudeModelList struct {
+	Data []struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"data"`
+}
+
+func (c *claudeClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	body, err := doGet(ctx, c.http, "https://api.anthropic.com/v1/models?limit=1000", map[string]string{
+		"x-api-key":         c.apiKey,
+		"anthropic-version": anthropicVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var list claudeModelList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("mind: decode model list: %w", err)
+	}
+	models := make([]ModelInfo, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, ModelInfo{Name: m.ID, ModifiedAt: m.CreatedAt})
+	}
+	return models, nil
+}
+
+type ollamaTags struct {
+	Models []struct {
+		Name       string    `json:"name"`
+		Size       int64     `json:"size"`
+		ModifiedAt time.Time `json:"modified_at"`
+	} `json:"models"`
+}
+
+func (c *ollamaClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	host := c.host
+	if host == "" {
+		host = "http://localhost:11434"
+	}
+	body, err := doGet(ctx, c.http, strings.TrimRight(host, "/")+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	var tags ollamaTags
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("mind: decode model list: %w", err)
+	}
+	models := make([]ModelInfo, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		models = append(models, ModelInfo{Name: m.Name, Size: m.Size, ModifiedAt: m.ModifiedAt})
+	}
+	return models, nil
+}
@@ -0,0 +1,122 @@
+package mind
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+func TestStopPayload(t *testing.T) {
+	tests := []struct {
+		provider string
+		stop     []string
+		path     string
+		want     any // nil when the field must be left out
+	}{
+		{"groq", []string{"###"}, "stop", []any{"###"}},
+		{"groq", nil, "stop", nil},
+		{"azure", []string{"###", "END"}, "stop", []any{"###", "END"}},
+		{"azure", nil, "stop", nil},
+		{"claude", []string{"```"}, "stop_sequences", []any{"```"}},
+		{"claude", nil, "stop_sequences", nil},
+		{"ollama", []string{"\n\n"}, "options.stop", []any{"\n\n"}},
+		{"ollama", nil, "options", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			payload := capturePayload(t, core.Config{AIProvider: tt.provider, Stop: tt.stop})
+			got, ok := lookup(payload, tt.path)
+			switch {
+			case tt.want == nil && ok:
+				t.Errorf("%s = %v, want it left out", tt.path, got)
+			case tt.want != nil && !reflect.DeepEqual(got, tt.want):
+				t.Errorf("%s = %#v, want %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopLimit(t *testing.T) {
+	five := []string{"a", "b", "c", "d", "e"}
+	for _, provider := range []string{"groq", "azure"} {
+		cfg := core.Config{AIProvider: provider, APIKey: "k", Stop: five, Azure: core.AzureConfig{Resource: "r", Deployment: "d"}}
+		_, err := newProviderClient(cfg)
+		var unsupported *UnsupportedFeatureError
+		if !errors.As(err, &unsupported) {
+			t.Errorf("%s with 5 stop sequences: err = %v, want *UnsupportedFeatureError", provider, err)
+		}
+	}
+	for _, provider := range []string{"claude", "ollama"} {
+		if _, err := newProviderClient(core.Config{AIProvider: provider, APIKey: "k", Stop: five}); err != nil {
+			t.Errorf("%s with 5 stop sequences: %v", provider, err)
+		}
+	}
+}
+
+func TestSeedPayload(t *testing.T) {
+	seed := func(v int64) *int64 { return &v }
+	tests := []struct {
+		provider string
+		seed     *int64
+		path     string
+		want     any // nil when the field must be left out
+	}{
+		{"groq", seed(42), "seed", 42.0},
+		{"groq", seed(0), "seed", 0.0},
+		{"groq", nil, "seed", nil},
+		{"azure", seed(42), "seed", 42.0},
+		{"azure", seed(0), "seed", 0.0},
+		{"azure", nil, "seed", nil},
+		{"ollama", seed(42), "options.seed", 42.0},
+		{"ollama", seed(0), "options.seed", 0.0},
+		{"ollama", nil, "options", nil},
+		{"claude", seed(42), "seed", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			payload := capturePayload(t, core.Config{AIProvider: tt.provider, Seed: tt.seed})
+			got, ok := lookup(payload, tt.path)
+			switch {
+			case tt.want == nil && ok:
+				t.Errorf("%s = %v, want it left out", tt.path, got)
+			case tt.want != nil && got != tt.want:
+				t.Errorf("%s = %#v, want %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeedUnsupported(t *testing.T) {
+	seed := int64(7)
+	tests := []struct {
+		cfg  core.Config
+		want []string // providers warned about
+	}{
+		{core.Config{AIProvider: "claude", Seed: &seed}, []string{"claude"}},
+		{core.Config{AIProvider: "claude"}, nil},
+		{core.Config{AIProvider: "groq", Seed: &seed}, nil},
+		{core.Config{AIProvider: "groq", Seed: &seed, Fallbacks: []string{"claude:claude-haiku-4-5"}}, []string{"claude"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, err := range UnsupportedFeatures(tt.cfg) {
+			if strings.HasPrefix(err.Feature, "seed") {
+				got = append(got, err.Provider)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UnsupportedFeatures(%s, fallbacks %v) warns for %v, want %v", tt.cfg.AIProvider, tt.cfg.Fallbacks, got, tt.want)
+		}
+	}
+}
+
+func TestSeedNegative(t *testing.T) {
+	seed := int64(-1)
+	_, err := newProviderClient(core.Config{AIProvider: "ollama", Seed: &seed})
+	if err == nil || err.Error() != "seed must be 0 or more (got -1)" {
+		t.Errorf("err = %v, want a negative seed rejected", err)
+	}
+}
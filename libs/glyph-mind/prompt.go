@@ -0,0 +1,48 @@
+package mind
+
+import "strings"
+
+// PromptBuilder assembles a prompt from titled sections so every tool
+// formats injected context the same way:
+//
+//	<instructions>
+//
+//	Current directory context:
+//	<body>
+//
+// The zero value is ready to use.
+type PromptBuilder struct {
+	sections []string
+}
+
+// NewPromptBuilder returns a builder whose prompt starts with base, the
+// untitled instructions.
+func NewPromptBuilder(base string) *PromptBuilder {
+	b := &PromptBuilder{}
+	b.AddSection("", base)
+	return b
+}
+
+// AddSection appends body under title, written as "title:" on its own line.
+// An empty title adds body as-is, and an empty body adds nothing, so callers
+// can pass optional context without checking it first. It returns b.
+func (b *PromptBuilder) AddSection(title, body string) *PromptBuilder {
+	if strings.TrimSpace(body) == "" {
+		return b
+	}
+	if title != "" {
+		body = title + ":\n" + body
+	}
+	b.sections = append(b.sections, body)
+	return b
+}
+
+// Len returns the length in bytes of the assembled prompt.
+func (b *PromptBuilder) Len() int {
+	return len(b.String())
+}
+
+// String returns the prompt, with sections separated by a blank line.
+func (b *PromptBuilder) String() string {
+	return strings.Join(b.sections, "\n\n")
+}
@@ -0,0 +1,91 @@
+package mind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// retryBaseDelay is the wait before the first retry; it doubles after each
+// further attempt. It is a variable so tests can shorten it.
+var retryBaseDelay = 500 * time.Millisecond
+
+// WithRetry returns a client that retries a request up to retries more
+// times when it fails before its first chunk, waiting 0.5s, 1s, 2s, ...
+// between attempts. Failures after the first chunk are never retried, so
+// it suits read-only generators whose whole request is safe to repeat.
+// Errors that a retry cannot fix, such as a rejected API key, are returned
+// at once; when every attempt fails the error is a *RetryExhaustedError.
+// A retries value below 1 returns client unchanged.
+func WithRetry(client Client, retries int) Client {
+	if retries < 1 {
+		return client
+	}
+	return &retryClient{client: client, retries: retries}
+}
+
+type retryClient struct {
+	client  Client
+	retries int
+}
+
+func (c *retryClient) Stream(ctx context.Context, system, user string) (*Stream, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		stream, err := c.client.Stream(ctx, system, user)
+		if err == nil {
+			stream, err = awaitFirstChunk(stream)
+		}
+		if err == nil || ctx.Err() != nil || !retryable(err) {
+			return stream, err
+		}
+		if attempt == c.retries {
+			return nil, &RetryExhaustedError{Attempts: attempt + 1, Err: err}
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// RetryExhaustedError is returned by a WithRetry client when every attempt
+// failed with a retryable error. It unwraps to the last attempt's error, so
+// errors.Is and errors.As still see the cause.
+type RetryExhaustedError struct {
+	Attempts int   // requests made, including the first
+	Err      error // error of the last attempt
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("provider unavailable after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// retryable reports whether err may go away on a second attempt: anything
+// but an oversized request or an HTTP 4xx response, except 408 (timeout)
+// and 429 (rate limit).
+func retryable(err error) bool {
+	var tooLarge *RequestTooLargeError
+	if errors.As(err, &tooLarge) {
+		return false
+	}
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return true
+	}
+	switch {
+	case httpErr.StatusCode == http.StatusRequestTimeout, httpErr.StatusCode == http.StatusTooManyRequests:
+		return true
+	case httpErr.StatusCode >= 400 && httpErr.StatusCode < 500:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package mind
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// answerBody is a groq stream answering "Hello".
+const answerBody = "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\ndata: [DONE]\n\n"
+
+// flaky starts a test server that fails the first requests with statuses,
+// one each, and answers "Hello" after that. It counts the requests in n.
+func flaky(t *testing.T, n *atomic.Int32, statuses ...int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if i := int(n.Add(1)) - 1; i < len(statuses) {
+			http.Error(w, `{"error":{"message":"try later"}}`, statuses[i])
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, answerBody)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// fastRetries shortens the wait between attempts for the test.
+func fastRetries(t *testing.T) {
+	delay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = delay })
+}
+
+func TestWithRetry(t *testing.T) {
+	fastRetries(t)
+
+	tests := []struct {
+		name     string
+		statuses []int
+		want     string // the answer, or "" for an error
+		requests int32
+	}{
+		{"503 then 200", []int{503}, "Hello", 2},
+		{"429 is retried", []int{429, 429}, "Hello", 3},
+		{"408 is retried", []int{408}, "Hello", 2},
+		{"401 fails at once", []int{401}, "", 1},
+		{"400 fails at once", []int{400, 400}, "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n atomic.Int32
+			srv := flaky(t, &n, tt.statuses...)
+			client := WithRetry(testClient(t, core.Config{AIProvider: "groq"}, srv), 2)
+			stream, err := client.Stream(t.Context(), "system", "user")
+			switch {
+			case tt.want == "" && err == nil:
+				t.Errorf("Stream: no error, want status %d", tt.statuses[0])
+			case tt.want != "" && err != nil:
+				t.Fatal(err)
+			case tt.want != "":
+				if got := drain(stream); got != tt.want {
+					t.Errorf("answer = %q, want %q", got, tt.want)
+				}
+			default:
+				var httpErr *HTTPError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.statuses[0] {
+					t.Errorf("err = %v, want an *HTTPError with status %d", err, tt.statuses[0])
+				}
+				var exhausted *RetryExhaustedError
+				if errors.As(err, &exhausted) {
+					t.Errorf("err = %v, want no retries", err)
+				}
+			}
+			if got := n.Load(); got != tt.requests {
+				t.Errorf("requests = %d, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestWithRetryExhausted(t *testing.T) {
+	fastRetries(t)
+
+	var n atomic.Int32
+	srv := flaky(t, &n, 503, 502, 503, 503)
+	_, err := WithRetry(testClient(t, core.Config{AIProvider: "groq"}, srv), 2).Stream(t.Context(), "system", "user")
+
+	var exhausted *RetryExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("err = %v, want a *RetryExhaustedError", err)
+	}
+	if exhausted.Attempts != 3 || n.Load() != 3 {
+		t.Errorf("Attempts = %d after %d requests, want 3", exhausted.Attempts, n.Load())
+	}
+	// The cause is still visible through the wrapper.
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 503 {
+		t.Errorf("err = %v, want it to wrap the last attempt's 503", err)
+	}
+}
+
+func TestWithRetryZero(t *testing.T) {
+	client := testClient(t, core.Config{AIProvider: "groq"}, serve(t, "text/event-stream", answerBody))
+	if got := WithRetry(client, 0); got != client {
+		t.Errorf("WithRetry(client, 0) = %T, want client unchanged", got)
+	}
+}
@@ -0,0 +1,74 @@
+package mind
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// ndjsonLine is an Ollama stream message carrying content.
+func ndjsonLine(content string, done bool) string {
+	reason := ""
+	if done {
+		reason = "stop"
+	}
+	return fmt.Sprintf(`{"message":{"content":%q},"done":%t,"done_reason":%q}`+"\n", content, done, reason)
+}
+
+func TestLineScannerLongLines(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"over bufio's 64 KiB default", 100 << 10},
+		{"at several MiB", 4 << 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			long := strings.Repeat("x", tt.size)
+			srv := serve(t, "application/x-ndjson", ndjsonLine("a", false)+ndjsonLine(long, false)+ndjsonLine("b", true))
+			stream, err := testClient(t, core.Config{AIProvider: "ollama"}, srv).Stream(t.Context(), "system", "user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := drain(stream); got != "a"+long+"b" {
+				t.Errorf("answer has %d bytes, want %d", len(got), len(long)+2)
+			}
+			if err := stream.Err(); err != nil {
+				t.Errorf("Err = %v", err)
+			}
+		})
+	}
+}
+
+func TestLineScannerOverCap(t *testing.T) {
+	long := strings.Repeat("x", maxLineSize+1)
+	srv := serve(t, "application/x-ndjson", ndjsonLine("a", false)+ndjsonLine(long, true))
+	stream, err := testClient(t, core.Config{AIProvider: "ollama"}, srv).Stream(t.Context(), "system", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := drain(stream); got != "a" {
+		t.Errorf("answer = %.20q, want %q", got, "a")
+	}
+	if err := stream.Err(); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Err = %v, want bufio.ErrTooLong", err)
+	}
+}
+
+func TestOllamaLengthTruncated(t *testing.T) {
+	body := ndjsonLine("partial", false) + `{"message":{"content":""},"done":true,"done_reason":"length"}` + "\n"
+	srv := serve(t, "application/x-ndjson", body)
+	stream, err := testClient(t, core.Config{AIProvider: "ollama"}, srv).Stream(t.Context(), "system", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(stream)
+	if !stream.Truncated() {
+		t.Error("Truncated = false for done_reason length")
+	}
+}
@@ -0,0 +1,121 @@
+package mind
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// redirectTransport sends every request to target, whatever its URL, so
+// clients with a fixed API URL, such as groq and claude, talk to a test
+// server.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// testClient builds the client for cfg.AIProvider with the test
+// credentials each provider needs, sending its requests to srv.
+func testClient(t *testing.T, cfg core.Config, srv *httptest.Server) Client {
+	t.Helper()
+	if cfg.APIKey == "" {
+		cfg.APIKey = "test-key"
+	}
+	if cfg.Azure.Deployment == "" {
+		cfg.Azure.Resource, cfg.Azure.Deployment = "test", "gpt-4o"
+	}
+	client, err := newProviderClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc := &http.Client{Transport: redirectTransport{target}}
+	switch c := client.(type) {
+	case *groqClient:
+		c.http = hc
+	case *azureClient:
+		c.http = hc
+	case *claudeClient:
+		c.http = hc
+	case *ollamaClient:
+		c.http = hc
+	default:
+		t.Fatalf("unexpected client %T", client)
+	}
+	return client
+}
+
+// capturePayload sends a prompt with cfg to a test server and returns the
+// decoded JSON body of the request.
+func capturePayload(t *testing.T, cfg core.Config) map[string]any {
+	t.Helper()
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies <- b
+	}))
+	t.Cleanup(srv.Close)
+
+	stream, err := testClient(t, cfg, srv).Stream(t.Context(), "system", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range stream.C {
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+// lookup returns the value at path, dot-separated keys such as
+// "options.stop", in a decoded JSON object.
+func lookup(payload map[string]any, path string) (any, bool) {
+	var v any = payload
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// serve starts a test server that replies to every request with body,
+// sent with the given Content-Type.
+func serve(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// drain reads s to the end and returns the text it delivered.
+func drain(s *Stream) string {
+	var b strings.Builder
+	for chunk := range s.C {
+		b.WriteString(chunk)
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package mind
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// sseEvent is one event of a server-sent events stream.
+type sseEvent struct {
+	Name string // the event field, "" when the event has none
+	Data string // the data lines, joined with "\n"
+}
+
+// payloads returns the JSON payloads of e: its data, or each data line on
+// its own when the server put several messages in one event by leaving
+// out the blank lines between them.
+func (e sseEvent) payloads() []string {
+	data := strings.TrimSpace(e.Data)
+	if !strings.Contains(data, "\n") || json.Valid([]byte(data)) {
+		return []string{data}
+	}
+	return strings.Split(data, "\n")
+}
+
+// sseReader splits a server-sent events stream into events, following
+// the parsing rules of the HTML spec: a blank line ends an event, lines
+// starting with ":" are comments (such as keep-alive heartbeats), "data"
+// lines accumulate, the space after "field:" is optional, and id, retry
+// and unknown fields are skipped. Unlike the spec, an event the server
+// did not end with a blank line is still delivered at the end of the
+// stream.
+type sseReader struct {
+	scanner *bufio.Scanner
+	event   sseEvent
+	first   bool // no line read yet
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{scanner: newLineScanner(r), first: true}
+}
+
+// Next reads the next event, reporting false at the end of the stream or
+// on a read error (see Err).
+func (r *sseReader) Next() bool {
+	var name string
+	var data strings.Builder
+	hasData := false
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if r.first {
+			// The stream may start with a byte order mark.
+			line = strings.TrimPrefix(line, "\uFEFF")
+			r.first = false
+		}
+		if line == "" {
+			if !hasData {
+				// An event without data is not dispatched.
+				name = ""
+				continue
+			}
+			r.event = sseEvent{Name: name, Data: data.String()}
+			return true
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			name = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		}
+	}
+	if hasData && r.scanner.Err() == nil {
+		r.event = sseEvent{Name: name, Data: data.String()}
+		return true
+	}
+	return false
+}
+
+// Event returns the event read by the last successful Next.
+func (r *sseReader) Event() sseEvent {
+	return r.event
+}
+
+// Err returns the read error that ended the stream, if any.
+func (r *sseReader) Err() error {
+	return r.scanner.Err()
+}
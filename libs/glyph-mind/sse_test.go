@@ -0,0 +1,146 @@
+package mind
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+func TestSSEReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []sseEvent
+	}{
+		{
+			name:  "spec form",
+			input: "data: {\"a\":1}\n\ndata: {\"a\":2}\n\n",
+			want:  []sseEvent{{Data: `{"a":1}`}, {Data: `{"a":2}`}},
+		},
+		{
+			name:  "no space after the colon",
+			input: "data:{\"a\":1}\n\n",
+			want:  []sseEvent{{Data: `{"a":1}`}},
+		},
+		{
+			name:  "only the first space is dropped",
+			input: "data:  x\n\n",
+			want:  []sseEvent{{Data: " x"}},
+		},
+		{
+			name:  "heartbeat comments",
+			input: ": keep-alive\n\n:\ndata: x\n: ping\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "event id and retry fields",
+			input: "id: 7\nretry: 3000\nevent: content_block_delta\ndata: x\n\nevent: ping\n\n",
+			want:  []sseEvent{{Name: "content_block_delta", Data: "x"}},
+		},
+		{
+			name:  "unknown fields",
+			input: "foo: bar\ndata: x\nbaz\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "crlf line endings",
+			input: "event: message\r\ndata: x\r\n\r\ndata: y\r\n\r\n",
+			want:  []sseEvent{{Name: "message", Data: "x"}, {Data: "y"}},
+		},
+		{
+			name:  "byte order mark",
+			input: "\uFEFFdata: x\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: {\ndata: \"a\": 1\ndata: }\n\n",
+			want:  []sseEvent{{Data: "{\n\"a\": 1\n}"}},
+		},
+		{
+			name:  "unterminated final event",
+			input: "data: x\n\ndata: [DONE]",
+			want:  []sseEvent{{Data: "x"}, {Data: "[DONE]"}},
+		},
+		{
+			name:  "event name does not leak into the next event",
+			input: "event: error\n\ndata: x\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "empty stream",
+			input: "",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSSEReader(strings.NewReader(tt.input))
+			var got []sseEvent
+			for r.Next() {
+				got = append(got, r.Event())
+			}
+			if err := r.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSEEventPayloads(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"single message", `{"a":1}`, []string{`{"a":1}`}},
+		{"multi-line JSON", "{\n\"a\": 1\n}", []string{"{\n\"a\": 1\n}"}},
+		{"run-together messages", "{\"a\":1}\n{\"a\":2}\n[DONE]", []string{`{"a":1}`, `{"a":2}`, "[DONE]"}},
+		{"surrounding space", " [DONE] \n", []string{"[DONE]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (sseEvent{Data: tt.data}).payloads(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("payloads = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// quirkyStream is the output of an OpenAI-compatible server that bends
+// the SSE format every way the parser accepts: a BOM, CRLF, heartbeats,
+// id and retry fields, no space after "data:", two messages run together
+// in one event, and no blank line after the last event.
+const quirkyStream = "\uFEFF: connected\r\n\r\n" +
+	"id: 1\r\nretry: 1000\r\ndata:{\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\r\n\r\n" +
+	": ping\r\n\r\n" +
+	"data: {\"choices\":[{\"delta\":{\"content\":\", \"}}]}\r\n" +
+	"data: {\"choices\":[{\"delta\":{\"content\":\"world\"}}]}\r\n\r\n" +
+	"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2}}\r\n\r\n" +
+	"data: [DONE]"
+
+func TestQuirkyServerStream(t *testing.T) {
+	srv := serve(t, "text/event-stream", quirkyStream)
+	for _, provider := range []string{"groq", "azure"} {
+		t.Run(provider, func(t *testing.T) {
+			stream, err := testClient(t, core.Config{AIProvider: provider}, srv).Stream(t.Context(), "system", "user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := drain(stream); got != "Hello, world" {
+				t.Errorf("answer = %q, want %q", got, "Hello, world")
+			}
+			if err := stream.Err(); err != nil {
+				t.Errorf("Err = %v", err)
+			}
+			if got, want := stream.Usage(), (Usage{InputTokens: 3, OutputTokens: 2}); got != want {
+				t.Errorf("usage = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package mind
+
+import (
+	"strings"
+	"time"
+)
+
+// StreamStats describes how a response streamed, for comparing the speed
+// of providers.
+type StreamStats struct {
+	// FirstChunk is the time from sending the request to the first chunk.
+	FirstChunk time.Duration
+	// Elapsed is the time from sending the request to the end of the
+	// response.
+	Elapsed time.Duration
+	// Chunks is the number of chunks delivered.
+	Chunks int
+	// Tokens is the output token count the provider reported or, when it
+	// reported none, an estimate from the text (see EstimateTokens).
+	Tokens int
+	// Estimated reports whether Tokens is an estimate.
+	Estimated bool
+}
+
+// TokensPerSecond returns the output rate once text started arriving, so
+// that the wait for the first chunk, mostly network and queueing, does not
+// count against the model. A response delivered in one chunk is measured
+// over Elapsed instead.
+func (s StreamStats) TokensPerSecond() float64 {
+	d := s.Elapsed - s.FirstChunk
+	if s.Chunks < 2 || d <= 0 {
+		d = s.Elapsed
+	}
+	if d <= 0 {
+		return 0
+	}
+	return float64(s.Tokens) / d.Seconds()
+}
+
+// WithStats returns a Stream that forwards s and records its StreamStats,
+// which Stats returns once the returned Stream's C is closed.
+func WithStats(s *Stream) *Stream {
+	out, ch := newStream(s.provider, s.model)
+	out.start, out.requestBytes = s.start, s.requestBytes
+	go func() {
+		stats := &StreamStats{}
+		var text strings.Builder
+		for chunk := range s.C {
+			if stats.Chunks == 0 {
+				stats.FirstChunk = time.Since(s.start)
+			}
+			stats.Chunks++
+			text.WriteString(chunk)
+			ch <- chunk
+		}
+		stats.Elapsed = time.Since(s.start)
+		stats.Tokens = s.usage.OutputTokens
+		if stats.Tokens == 0 {
+			stats.Tokens, stats.Estimated = EstimateTokens(text.String()), true
+		}
+		out.usage, out.truncated, out.filtered, out.err = s.usage, s.truncated, s.filtered, s.err
+		out.stats = stats
+		close(ch)
+	}()
+	return out
+}
+
+// Stats returns the StreamStats recorded by WithStats, and false when s
+// was not wrapped by it. Like the other accessors, it is valid once C has
+// been closed.
+func (s *Stream) Stats() (StreamStats, bool) {
+	if s.stats == nil {
+		return StreamStats{}, false
+	}
+	return *s.stats, true
+}
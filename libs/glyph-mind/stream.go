@@ -0,0 +1,124 @@
+package mind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// Usage holds the token counts a provider reported for one response.
+// Fields are zero when the provider did not report them.
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// Total returns the sum of input and output tokens.
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Stream is an in-flight model response. Read text chunks from C until it
+// is closed; the accessor methods are valid once C has been closed.
+type Stream struct {
+	// C delivers text chunks and is closed when the response ends.
+	C <-chan string
+
+	provider  string
+	model     string
+	usage     Usage
+	truncated bool
+	filtered  bool
+	err       error
+	start     time.Time
+
+	requestBytes int
+	stats        *StreamStats // set by WithStats
+}
+
+// NewStream wraps ch as a Stream. It is meant for Client implementations
+// outside this package, such as test doubles.
+func NewStream(ch <-chan string) *Stream {
+	return &Stream{C: ch, start: time.Now()}
+}
+
+// newStream returns a Stream for model of provider and the channel its
+// producer writes to.
+func newStream(provider, model string) (*Stream, chan string) {
+	ch := make(chan string, 64)
+	return &Stream{C: ch, provider: provider, model: model, start: time.Now()}, ch
+}
+
+// Provider returns the provider that served the request, or "" when
+// unknown. With fallbacks configured it can differ from ai_provider.
+func (s *Stream) Provider() string {
+	return s.provider
+}
+
+// Model returns the model the request was sent to, or "" when unknown.
+func (s *Stream) Model() string {
+	return s.model
+}
+
+// RequestBytes returns the size of the request body sent, or 0 when
+// unknown.
+func (s *Stream) RequestBytes() int {
+	return s.requestBytes
+}
+
+// Usage returns the token usage reported by the provider.
+func (s *Stream) Usage() Usage {
+	return s.usage
+}
+
+// Truncated reports whether the provider stopped the response because it
+// hit a length limit (the output token limit, or for Ollama also a full
+// context window) rather than because the answer was complete.
+func (s *Stream) Truncated() bool {
+	return s.truncated
+}
+
+// Filtered reports whether the provider withheld the response, such as
+// OpenAI-style APIs ending with finish_reason "content_filter" or Claude
+// with stop_reason "refusal". Ollama does not report it.
+func (s *Stream) Filtered() bool {
+	return s.filtered
+}
+
+// Err returns why the response ended early, or nil if it completed. A
+// context deadline or cancellation is reported as an *core.AppError that
+// wraps context.DeadlineExceeded or context.Canceled.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Fail records err as the stream error; producers call it before closing
+// C. When ctx is done, the context error is reported instead, since read
+// errors are then only its symptom. A nil err is ignored.
+func (s *Stream) Fail(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = contextError(ctxErr, time.Since(s.start))
+	}
+	s.err = err
+}
+
+// contextError turns a context error into a message for the user.
+func contextError(err error, elapsed time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		precision := time.Second
+		if elapsed < time.Second {
+			precision = time.Millisecond
+		}
+		return &core.AppError{
+			Msg: fmt.Sprintf("response timed out after %s", elapsed.Round(precision)),
+			Err: err,
+		}
+	}
+	return &core.AppError{Msg: "response canceled", Err: err}
+}
@@ -0,0 +1,102 @@
+package mind
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// stall starts a test server that sends head, if any, then waits until
+// the client goes away.
+func stall(t *testing.T, contentType, head string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Until the body is read, the server does not notice the client
+		// leaving, and the handler would outlive the test.
+		io.Copy(io.Discard, r.Body)
+		if head != "" {
+			w.Header().Set("Content-Type", contentType)
+			io.WriteString(w, head)
+			http.NewResponseController(w).Flush()
+		}
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStreamDeadline(t *testing.T) {
+	tests := []struct {
+		provider, contentType, head string
+		want                        string // the text that arrived in time
+	}{
+		{"groq", "text/event-stream", "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n", "Hel"},
+		{"ollama", "application/x-ndjson", "{\"message\":{\"content\":\"Hel\"},\"done\":false}\n", "Hel"},
+		{"claude", "text/event-stream", "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hel\"}}\n\n", "Hel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			srv := stall(t, tt.contentType, tt.head)
+			ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+			defer cancel()
+			stream, err := testClient(t, core.Config{AIProvider: tt.provider}, srv).Stream(ctx, "system", "user")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := drain(stream); got != tt.want {
+				t.Errorf("answer = %q, want %q", got, tt.want)
+			}
+			checkTimeout(t, stream.Err())
+		})
+	}
+}
+
+func TestStreamDeadlineBeforeHeaders(t *testing.T) {
+	srv := stall(t, "", "")
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	stream, err := testClient(t, core.Config{AIProvider: "groq"}, srv).Stream(ctx, "system", "user")
+	if err == nil {
+		drain(stream)
+		err = stream.Err()
+	}
+	checkTimeout(t, err)
+}
+
+func TestStreamCanceled(t *testing.T) {
+	srv := stall(t, "text/event-stream", "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+	ctx, cancel := context.WithCancel(t.Context())
+	stream, err := testClient(t, core.Config{AIProvider: "groq"}, srv).Stream(ctx, "system", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-stream.C
+	cancel()
+	drain(stream)
+	err = stream.Err()
+	if !errors.Is(err, context.Canceled) || !strings.HasPrefix(err.Error(), "response canceled") {
+		t.Errorf("Err = %v, want \"response canceled\" wrapping context.Canceled", err)
+	}
+}
+
+// checkTimeout fails t unless err is the deadline error Fail reports.
+func checkTimeout(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Err = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after") {
+		t.Errorf("Err = %q, want it to say how long the response took", err)
+	}
+	var appErr *core.AppError
+	if !errors.As(err, &appErr) {
+		t.Errorf("Err = %T, want a *core.AppError", err)
+	}
+}
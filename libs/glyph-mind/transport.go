@@ -0,0 +1,96 @@
+package mind
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// Connection pool settings for provider requests. Go's default keeps only
+// two idle connections per host, so concurrent calls to one provider, as
+// with ask --compare, would open new TLS connections once they finish.
+const (
+	maxIdleConnsPerHost = 8
+	idleConnTimeout     = 90 * time.Second
+)
+
+// transportKey identifies the network settings an HTTP client was built
+// for.
+type transportKey struct {
+	proxy    string
+	caBundle string
+	insecure bool
+}
+
+var (
+	httpClientsMu sync.Mutex
+	httpClients   = map[transportKey]*http.Client{}
+)
+
+// HTTPClient returns the HTTP client used for provider requests with cfg's
+// network settings. Clients are shared within the process, one per distinct
+// http_proxy, ca_bundle and insecure_skip_verify combination, so every
+// call, including concurrent and batched ones, reuses kept-alive
+// connections.
+//
+// Without http_proxy the client honors HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY; with it, that proxy is used for every request. ca_bundle adds
+// the certificates in a PEM file to the system roots.
+func HTTPClient(cfg core.Config) (*http.Client, error) {
+	key := transportKey{proxy: cfg.HTTPProxy, caBundle: cfg.CABundle, insecure: cfg.InsecureSkipVerify}
+	httpClientsMu.Lock()
+	defer httpClientsMu.Unlock()
+	if c, ok := httpClients[key]; ok {
+		return c, nil
+	}
+	c, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	httpClients[key] = c
+	return c, nil
+}
+
+// newHTTPClient builds a client with a copy of the default transport, tuned
+// for connection reuse and configured from cfg.
+func newHTTPClient(cfg core.Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+
+	if cfg.HTTPProxy != "" {
+		proxy, err := url.Parse(cfg.HTTPProxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, &core.AppError{Msg: fmt.Sprintf("invalid http_proxy %q: expected a URL such as http://proxy:3128", cfg.HTTPProxy), Err: err}
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if cfg.CABundle != "" || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+		if cfg.CABundle != "" {
+			pem, err := os.ReadFile(cfg.CABundle)
+			if err != nil {
+				return nil, &core.AppError{Msg: "cannot read ca_bundle", Err: err}
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, &core.AppError{Msg: fmt.Sprintf("ca_bundle %s contains no PEM certificates", cfg.CABundle)}
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
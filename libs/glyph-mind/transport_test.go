@@ -0,0 +1,126 @@
+package mind
+
+import (
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// tlsServer starts a TLS test server that answers every request with "ok"
+// and counts the connections opened to it. The returned config trusts the
+// server's certificate through ca_bundle.
+func tlsServer(tb testing.TB) (*httptest.Server, core.Config, *atomic.Int64) {
+	tb.Helper()
+	var conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	tb.Cleanup(srv.Close)
+
+	bundle := filepath.Join(tb.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	return srv, core.Config{CABundle: bundle}, &conns
+}
+
+// get sends a GET to url with c and reads the whole reply, as a provider
+// client does, so the connection goes back to the pool.
+func get(tb testing.TB, c *http.Client, url string) {
+	tb.Helper()
+	resp, err := c.Get(url)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func TestHTTPClientShared(t *testing.T) {
+	a, err := HTTPClient(core.Config{HTTPProxy: "http://proxy.test:3128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := HTTPClient(core.Config{HTTPProxy: "http://proxy.test:3128", AIProvider: "groq"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Error("the same network settings got two clients")
+	}
+	c, err := HTTPClient(core.Config{HTTPProxy: "http://other.test:3128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == c {
+		t.Error("a different http_proxy got the same client")
+	}
+}
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+	srv, cfg, conns := tlsServer(t)
+	for range 10 {
+		c, err := HTTPClient(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		get(t, c, srv.URL)
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("10 sequential requests opened %d connections, want 1", n)
+	}
+}
+
+// BenchmarkSequentialRequests measures a batch of requests sent one after
+// another, as a tool splitting its input into several calls would send
+// them, through the shared client and through a new client per request.
+func BenchmarkSequentialRequests(b *testing.B) {
+	const batch = 10
+	srv, cfg, conns := tlsServer(b)
+	clients := map[string]func() *http.Client{
+		"shared": func() *http.Client {
+			c, err := HTTPClient(cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			return c
+		},
+		"new": func() *http.Client {
+			c, err := newHTTPClient(cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			return c
+		},
+	}
+	for _, name := range []string{"shared", "new"} {
+		b.Run(name, func(b *testing.B) {
+			conns.Store(0)
+			for b.Loop() {
+				for range batch {
+					c := clients[name]()
+					get(b, c, srv.URL)
+					if name == "new" {
+						c.CloseIdleConnections()
+					}
+				}
+			}
+			b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+		})
+	}
+}
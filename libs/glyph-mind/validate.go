@@ -0,0 +1,74 @@
+package mind
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// ErrUnrecognizedModel is returned (wrapped) by CheckModel when a model name
+// matches no known naming scheme. It is meant as a warning: new models
+// appear faster than this table is updated.
+var ErrUnrecognizedModel = errors.New("unrecognized model name")
+
+// modelPrefixes lists the name prefixes of the models each provider serves.
+// Groq and Ollama both host open models, so some prefixes appear under both.
+var modelPrefixes = map[string][]string{
+	"claude": {"claude-"},
+	"groq": {
+		"llama-", "llama3-", "meta-llama/", "openai/", "gemma", "mixtral-",
+		"qwen", "deepseek-", "moonshotai/", "mistral-saba", "compound",
+	},
+	"ollama": {
+		"llama", "codellama", "qwen", "mistral", "mixtral", "gemma", "phi",
+		"deepseek", "starcoder", "granite", "gpt-oss", "nomic",
+	},
+}
+
+// modelProviders returns the providers whose naming scheme matches model,
+// sorted. Names with a ":tag" suffix are Ollama's.
+func modelProviders(model string) []string {
+	model = strings.ToLower(model)
+	if strings.Contains(model, ":") {
+		return []string{"ollama"}
+	}
+	var providers []string
+	for provider, prefixes := range modelPrefixes {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(model, prefix) {
+				providers = append(providers, provider)
+				break
+			}
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// CheckModel reports whether model plausibly belongs to provider. It returns
+// nil on a match, an *core.AppError naming the right provider when model
+// clearly belongs to another one, and an error wrapping ErrUnrecognizedModel
+// when the name matches no known provider. Unknown providers are not
+// checked.
+func CheckModel(provider, model string) error {
+	provider = normalizeProvider(provider)
+	if _, ok := modelPrefixes[provider]; !ok {
+		return nil
+	}
+	providers := modelProviders(model)
+	if len(providers) == 0 {
+		return fmt.Errorf("model %q is not a known model name for %s: %w", model, provider, ErrUnrecognizedModel)
+	}
+	for _, p := range providers {
+		if p == provider {
+			return nil
+		}
+	}
+	return &core.AppError{
+		Msg: fmt.Sprintf("model %q is not served by %s; it looks like a %s model (try --provider %s)",
+			model, provider, strings.Join(providers, " or "), providers[0]),
+	}
+}
@@ -0,0 +1,152 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Encrypted files start with a fixed header so Load can tell them apart from
+// plain JSON and report a clear error instead of a decode failure:
+//
+//	magic (8) | version (1) | salt (16) | nonce (12) | AES-GCM ciphertext
+const (
+	encMagic      = "GLYPHENC"
+	encVersion    = 1
+	encSaltSize   = 16
+	encKeySize    = 32
+	encIterations = 600_000
+)
+
+// ErrWrongKey is returned by Load when an encrypted file cannot be decrypted,
+// which almost always means the key is wrong.
+var ErrWrongKey = errors.New("store: cannot decrypt data file (wrong key?)")
+
+// ErrEncrypted is returned by a plain Store's Load when the file on disk is
+// encrypted.
+var ErrEncrypted = errors.New("store: data file is encrypted; a key is required")
+
+// cipherState derives and caches the AES key for a passphrase. The derived
+// key is reused for as long as the file keeps the same salt, so a process
+// pays the key-derivation cost once.
+type cipherState struct {
+	passphrase []byte
+
+	mu   sync.Mutex
+	salt []byte
+	key  []byte
+}
+
+// NewEncryptedStore creates a Store whose file is AES-256-GCM encrypted.
+// The AES key is derived from key with PBKDF2-SHA256 and a random per-file
+// salt, so key may be a passphrase (for example read from an environment
+// variable). An existing plain JSON file is read as-is and encrypted on the
+// next Save.
+func NewEncryptedStore[T any](path string, key []byte) *Store[T] {
+	return &Store[T]{
+		path: path,
+		enc:  &cipherState{passphrase: key},
+	}
+}
+
+// open decrypts data read from disk. For plain stores it only guards against
+// reading an encrypted file.
+func (c *cipherState) open(data []byte) ([]byte, error) {
+	isEncrypted := bytes.HasPrefix(data, []byte(encMagic))
+	if c == nil {
+		if isEncrypted {
+			return nil, ErrEncrypted
+		}
+		return data, nil
+	}
+	if !isEncrypted {
+		return data, nil
+	}
+
+	header := len(encMagic) + 1 + encSaltSize
+	if len(data) < header {
+		return nil, ErrWrongKey
+	}
+	if v := data[len(encMagic)]; v != encVersion {
+		return nil, fmt.Errorf("store: unsupported encryption version %d", v)
+	}
+	salt := data[len(encMagic)+1 : header]
+
+	gcm, err := c.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	rest := data[header:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, ErrWrongKey
+	}
+	nonce, ciphertext := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, data[:header])
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+// seal encrypts data for writing. Plain stores return data unchanged.
+func (c *cipherState) seal(data []byte) ([]byte, error) {
+	if c == nil {
+		return data, nil
+	}
+
+	c.mu.Lock()
+	salt := c.salt
+	c.mu.Unlock()
+	if salt == nil {
+		salt = make([]byte, encSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("store: generate salt: %w", err)
+		}
+	}
+
+	gcm, err := c.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("store: generate nonce: %w", err)
+	}
+
+	header := make([]byte, 0, len(encMagic)+1+encSaltSize)
+	header = append(header, encMagic...)
+	header = append(header, encVersion)
+	header = append(header, salt...)
+
+	out := make([]byte, 0, len(header)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, header...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, header), nil
+}
+
+// aead returns an AES-GCM cipher for the key derived with salt.
+func (c *cipherState) aead(salt []byte) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key == nil || !bytes.Equal(c.salt, salt) {
+		key, err := pbkdf2.Key(sha256.New, string(c.passphrase), salt, encIterations, encKeySize)
+		if err != nil {
+			return nil, fmt.Errorf("store: derive key: %w", err)
+		}
+		c.key = key
+		c.salt = append([]byte(nil), salt...)
+	}
+
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, fmt.Errorf("store: init cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
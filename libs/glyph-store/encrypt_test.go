@@ -0,0 +1,130 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// note is the item type the store tests save.
+type note struct {
+	Entry
+	Text string `json:"text"`
+}
+
+var notes = []note{
+	{Entry{ID: "1"}, "kubectl get pods"},
+	{Entry{ID: "2"}, "https://go.dev"},
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	if err := NewEncryptedStore[note](path, []byte("secret")).Save(notes); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(encMagic)) || data[len(encMagic)] != encVersion {
+		t.Errorf("file starts %q, want the %s header", data[:min(len(data), 9)], encMagic)
+	}
+	if bytes.Contains(data, []byte("kubectl")) {
+		t.Error("the file holds the text in the clear")
+	}
+
+	// A new store, as in the next run, derives the key again.
+	got, err := NewEncryptedStore[note](path, []byte("secret")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, notes) {
+		t.Errorf("Load = %+v, want %+v", got, notes)
+	}
+}
+
+func TestEncryptedWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	if err := NewEncryptedStore[note](path, []byte("secret")).Save(notes); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEncryptedStore[note](path, []byte("guess")).Load(); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Load with the wrong key: err = %v, want ErrWrongKey", err)
+	}
+
+	// A damaged file reads as a wrong key too, never as garbage.
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEncryptedStore[note](path, []byte("secret")).Load(); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Load of a damaged file: err = %v, want ErrWrongKey", err)
+	}
+}
+
+func TestPlainStoreReadsEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	if err := NewEncryptedStore[note](path, []byte("secret")).Save(notes); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStore[note](path).Load(); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("plain Load of an encrypted file: err = %v, want ErrEncrypted", err)
+	}
+}
+
+func TestEncryptedUpgradesPlainFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	if err := NewStore[note](path).Save(notes); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewEncryptedStore[note](path, []byte("secret"))
+	items, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, notes) {
+		t.Fatalf("Load of the plain file = %+v, want %+v", items, notes)
+	}
+	if data, _ := os.ReadFile(path); bytes.HasPrefix(data, []byte(encMagic)) {
+		t.Error("Load alone encrypted the file")
+	}
+
+	if err := s.Save(items); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.HasPrefix(data, []byte(encMagic)) {
+		t.Errorf("file after Save starts %q, want it encrypted", data[:min(len(data), 9)])
+	}
+	got, err := NewEncryptedStore[note](path, []byte("secret")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, notes) {
+		t.Errorf("Load after the upgrade = %+v, want %+v", got, notes)
+	}
+}
+
+func TestWithPerm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	// A temp file left by an interrupted Save, with looser permissions.
+	if err := os.WriteFile(path+".tmp", []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewEncryptedStore[note](path, []byte("secret")).WithPerm(0o600)
+	if err := s.Save(notes); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %o, want 600", perm)
+	}
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// NewJSONLStore creates a Store backed by a JSON Lines (ndjson) file at the
+// given path: one compact JSON object per line instead of one indented
+// array.
+//
+// The tradeoff is write cost against readability. Append and AppendAll add
+// lines to the end of the file without reading it, so appending stays cheap
+// as the file grows, while a JSON array store loads and rewrites every entry.
+// Load still reads the whole file, and Save, Compact and Update (and so any
+// delete or edit) rewrite it. Lines are not pretty-printed, and a file is
+// only valid line by line: tools that expect a single JSON document cannot
+// read it. Appends are not atomic the way Save's rename is, so a crash
+// mid-append can leave a truncated last line, which Load reports as a decode
+// error for that line. JSONL stores cannot be encrypted.
+func NewJSONLStore[T any](path string) *Store[T] {
+	return &Store[T]{path: path, jsonl: true}
+}
+
+// decodeLines decodes one item per non-blank line of data.
+func decodeLines[T any](path string, data []byte) ([]T, error) {
+	items := []T{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("store: decode %s line %d: %w", path, n, err)
+		}
+		items = append(items, item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("store: read %s: %w", path, err)
+	}
+	return items, nil
+}
+
+// encodeLines encodes items as one JSON object per line.
+func encodeLines[T any](items []T) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// appendLines writes items to the end of a JSONL file, creating it if
+// needed. A missing newline at the end of the existing file, left by a hand
+// edit, is added first so the new line starts on its own.
+func (s *Store[T]) appendLines(items []T) error {
+	if err := s.ensureDir(); err != nil {
+		return err
+	}
+	data, err := encodeLines(items)
+	if err != nil {
+		return fmt.Errorf("store: encode: %w", err)
+	}
+
+	perm := s.perm
+	if perm == 0 {
+		perm = 0o644
+	}
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, perm)
+	if err != nil {
+		return fmt.Errorf("store: open %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+			return fmt.Errorf("store: read %s: %w", s.path, err)
+		}
+		if last[0] != '\n' {
+			data = append([]byte{'\n'}, data...)
+		}
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("store: append to %s: %w", s.path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONLAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	s := NewJSONLStore[note](path)
+	for _, n := range notes {
+		if err := s.Append(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	more := []note{{Entry{ID: "3"}, "three"}, {Entry{ID: "4"}, "four"}}
+	if err := s.AppendAll(more); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(lines) != 4 {
+		t.Errorf("file has %d lines, want one per item:\n%s", len(lines), data)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append(append([]note{}, notes...), more...); !reflect.DeepEqual(got, want) {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+}
+
+func TestJSONLAppendAfterMissingNewline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	// As left by a hand edit: blank lines, and no newline at the end.
+	if err := os.WriteFile(path, []byte("{\"id\":\"1\",\"text\":\"one\"}\n\n{\"id\":\"2\",\"text\":\"two\"}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewJSONLStore[note](path)
+	if err := s.Append(note{Entry{ID: "3"}, "three"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, n := range got {
+		ids = append(ids, n.ID)
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %q, want %q", ids, want)
+	}
+}
+
+func TestJSONLTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	// A crash mid-append leaves part of the last line.
+	if err := os.WriteFile(path, []byte("{\"id\":\"1\",\"text\":\"one\"}\n{\"id\":\"2\",\"te"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewJSONLStore[note](path).Load()
+	if err == nil || !strings.Contains(err.Error(), "log.jsonl line 2") {
+		t.Errorf("Load of a truncated line: err = %v, want a decode error naming line 2", err)
+	}
+}
+
+func TestJSONLSaveRewrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	s := NewJSONLStore[note](path).WithPerm(0o600)
+	if err := s.AppendAll(notes); err != nil {
+		t.Fatal(err)
+	}
+	removed, err := s.DeleteFunc(func(n note) bool { return n.ID == "1" })
+	if err != nil || removed != 1 {
+		t.Fatalf("DeleteFunc = %d, %v; want 1 removed", removed, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"id\":\"2\",\"created_at\":\"0001-01-01T00:00:00Z\",\"text\":\"https://go.dev\"}\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, %v; want 600", info.Mode().Perm(), err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -14,6 +16,18 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// GetID returns the entry ID, making Entry (and any type embedding it)
+// satisfy Identifiable.
+func (e Entry) GetID() string {
+	return e.ID
+}
+
+// Identifiable is implemented by stored types that carry a unique ID.
+// The ID-aware Store helpers (Get, Has) require T to implement it.
+type Identifiable interface {
+	GetID() string
+}
+
 // NewEntry creates an Entry with a timestamp-based ID and current time.
 func NewEntry() Entry {
 	return Entry{
@@ -24,7 +38,14 @@ func NewEntry() Entry {
 
 // Store is a generic, JSON-file-backed store for any type T.
 type Store[T any] struct {
-	path string
+	path  string
+	enc   *cipherState // nil for plain JSON files
+	perm  os.FileMode  // 0 means the default 0o644
+	jsonl bool         // one object per line; see NewJSONLStore
+
+	// mu serializes read-modify-write operations (Append, AppendAll,
+	// Compact, Update, DeleteFunc) within a process.
+	mu sync.Mutex
 }
 
 // NewStore creates a Store backed by a JSON file at the given path.
@@ -33,6 +54,13 @@ func NewStore[T any](path string) *Store[T] {
 	return &Store[T]{path: path}
 }
 
+// WithPerm sets the permission bits Save writes the file with, for stores
+// holding data that other users should not read. It returns s.
+func (s *Store[T]) WithPerm(perm os.FileMode) *Store[T] {
+	s.perm = perm
+	return s
+}
+
 func (s *Store[T]) ensureDir() error {
 	dir := filepath.Dir(s.path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -55,6 +83,12 @@ func (s *Store[T]) Load() ([]T, error) {
 	if err != nil {
 		return nil, fmt.Errorf("store: read %s: %w", s.path, err)
 	}
+	if data, err = s.enc.open(data); err != nil {
+		return nil, err
+	}
+	if s.jsonl {
+		return decodeLines[T](s.path, data)
+	}
 
 	var items []T
 	if err := json.Unmarshal(data, &items); err != nil {
@@ -69,23 +103,51 @@ func (s *Store[T]) Save(items []T) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(items, "", "  ")
+	var data []byte
+	var err error
+	if s.jsonl {
+		data, err = encodeLines(items)
+	} else {
+		data, err = json.MarshalIndent(items, "", "  ")
+	}
 	if err != nil {
 		return fmt.Errorf("store: encode: %w", err)
 	}
+	if data, err = s.enc.seal(data); err != nil {
+		return err
+	}
 
+	perm := s.perm
+	if perm == 0 {
+		perm = 0o644
+	}
 	tmp := s.path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	if err := os.WriteFile(tmp, data, perm); err != nil {
 		return fmt.Errorf("store: write temp file: %w", err)
 	}
+	if s.perm != 0 {
+		// WriteFile only applies perm to new files; enforce it on a
+		// leftover temp file too.
+		if err := os.Chmod(tmp, perm); err != nil {
+			return fmt.Errorf("store: chmod temp file: %w", err)
+		}
+	}
 	if err := os.Rename(tmp, s.path); err != nil {
 		return fmt.Errorf("store: rename temp file: %w", err)
 	}
 	return nil
 }
 
-// Append loads existing entries, appends item, and saves.
+// Append loads existing entries, appends item, and saves. A JSONL store
+// writes only the new line. Use AppendAll to add many items at once.
 func (s *Store[T]) Append(item T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jsonl {
+		return s.appendLines([]T{item})
+	}
+
 	items, err := s.Load()
 	if err != nil {
 		return err
@@ -93,3 +155,121 @@ func (s *Store[T]) Append(item T) error {
 	items = append(items, item)
 	return s.Save(items)
 }
+
+// AppendAll appends items with a single Load and Save, holding the write
+// lock for the whole batch. It is the bulk form of Append, for imports.
+func (s *Store[T]) AppendAll(items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jsonl {
+		return s.appendLines(items)
+	}
+	existing, err := s.Load()
+	if err != nil {
+		return err
+	}
+	return s.Save(append(existing, items...))
+}
+
+// Compact loads all entries, sorts them with less (stable, so equal items
+// keep their relative order) and rewrites the file cleanly.
+func (s *Store[T]) Compact(less func(a, b T) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.Load()
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+	return s.Save(items)
+}
+
+// DeleteFunc removes every item for which del returns true and saves the
+// rest, returning how many were removed. Nothing is written when none is.
+func (s *Store[T]) DeleteFunc(del func(item T) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.Load()
+	if err != nil {
+		return 0, err
+	}
+	kept := items[:0]
+	for _, item := range items {
+		if !del(item) {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(items) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.Save(kept)
+}
+
+// Get returns the item whose ID equals id. The boolean reports whether a
+// match was found. T must implement Identifiable.
+func (s *Store[T]) Get(id string) (T, bool, error) {
+	var zero T
+	items, err := s.Load()
+	if err != nil {
+		return zero, false, err
+	}
+	for _, item := range items {
+		itemID, err := idOf(item)
+		if err != nil {
+			return zero, false, err
+		}
+		if itemID == id {
+			return item, true, nil
+		}
+	}
+	return zero, false, nil
+}
+
+// Update applies fn to the item whose ID equals id and saves the result.
+// The boolean reports whether a match was found; nothing is written when it
+// is false. T must implement Identifiable.
+func (s *Store[T]) Update(id string, fn func(item *T)) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.Load()
+	if err != nil {
+		return false, err
+	}
+	for i := range items {
+		itemID, err := idOf(items[i])
+		if err != nil {
+			return false, err
+		}
+		if itemID == id {
+			fn(&items[i])
+			return true, s.Save(items)
+		}
+	}
+	return false, nil
+}
+
+// Has reports whether an item with the given ID exists.
+// T must implement Identifiable.
+func (s *Store[T]) Has(id string) (bool, error) {
+	_, ok, err := s.Get(id)
+	return ok, err
+}
+
+// idOf returns the ID of item, or an error if its type is not Identifiable.
+func idOf(item any) (string, error) {
+	ident, ok := item.(Identifiable)
+	if !ok {
+		return "", fmt.Errorf("store: %T does not implement Identifiable", item)
+	}
+	return ident.GetID(), nil
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestAppendAll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	s := NewStore[note](path)
+	if err := s.Append(notes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendAll(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendAll(notes[1:]); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, notes) {
+		t.Errorf("Load = %+v, want %+v", got, notes)
+	}
+}
+
+func TestAppendAllConcurrent(t *testing.T) {
+	for _, jsonl := range []bool{false, true} {
+		t.Run(fmt.Sprint("jsonl=", jsonl), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pins.json")
+			s := NewStore[note](path)
+			if jsonl {
+				s = NewJSONLStore[note](path)
+			}
+			// Batches saved at once must not lose each other's items.
+			var wg sync.WaitGroup
+			for b := range 8 {
+				batch := make([]note, 50)
+				for i := range batch {
+					batch[i] = note{Entry{ID: fmt.Sprintf("%d-%d", b, i)}, "text"}
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := s.AppendAll(batch); err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			got, err := s.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 400 {
+				t.Fatalf("Load = %d items, want 400", len(got))
+			}
+			// Each batch stays in one piece.
+			for i := 0; i < len(got); i += 50 {
+				var b int
+				fmt.Sscanf(got[i].ID, "%d-", &b)
+				for j := range 50 {
+					if want := fmt.Sprintf("%d-%d", b, j); got[i+j].ID != want {
+						t.Fatalf("item %d = %s, want %s", i+j, got[i+j].ID, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetHas(t *testing.T) {
+	s := NewStore[note](filepath.Join(t.TempDir(), "pins.json"))
+	if ok, err := s.Has("1"); err != nil || ok {
+		t.Errorf("Has on a missing file = %v, %v; want false", ok, err)
+	}
+	if err := s.Save(notes); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok, err := s.Get("2")
+	if err != nil || !ok || !reflect.DeepEqual(got, notes[1]) {
+		t.Errorf("Get(2) = %+v, %v, %v; want %+v", got, ok, err, notes[1])
+	}
+	// IDs match exactly, never by prefix.
+	for _, id := range []string{"3", "", "1 "} {
+		if got, ok, err := s.Get(id); err != nil || ok || got != (note{}) {
+			t.Errorf("Get(%q) = %+v, %v, %v; want no match", id, got, ok, err)
+		}
+	}
+	if ok, err := s.Has("1"); err != nil || !ok {
+		t.Errorf("Has(1) = %v, %v; want true", ok, err)
+	}
+}
+
+func TestGetNotIdentifiable(t *testing.T) {
+	type plain struct{ Text string }
+	s := NewStore[plain](filepath.Join(t.TempDir(), "plain.json"))
+	if err := s.Save([]plain{{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Get("a"); err == nil {
+		t.Error("Get on a type without GetID returned no error")
+	}
+	if _, err := s.Has("a"); err == nil {
+		t.Error("Has on a type without GetID returned no error")
+	}
+}
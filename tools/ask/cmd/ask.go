@@ -3,10 +3,10 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"strings"
 
+	cli "github.com/reky0/glyph-cli"
 	core "github.com/reky0/glyph-core"
 	ink "github.com/reky0/glyph-ink"
 	mind "github.com/reky0/glyph-mind"
@@ -17,52 +17,298 @@ import (
 const systemPromptTmpl = `You are a helpful terminal assistant. Be concise. Use plain text, no markdown headers.
 When relevant, prefer showing commands over explaining them.`
 
+// dirContextTitle heads the directory context section of system prompts.
+const dirContextTitle = "Current directory context"
+
+// askSpinnerMessages rotate on stderr while ask waits for an answer.
+var askSpinnerMessages = []string{"thinking…", "still thinking…"}
+
 func runAsk(cmd *cobra.Command, args []string) error {
 	question := strings.Join(args, " ")
+	title := question
 	noContext, _ := cmd.Flags().GetBool("no-context")
+	templateName, _ := cmd.Flags().GetString("template")
 
-	// Read piped stdin if available.
-	stat, _ := os.Stdin.Stat()
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
-		piped, err := io.ReadAll(os.Stdin)
-		if err == nil && len(piped) > 0 {
-			question = string(piped) + "\n\n" + question
+	asJSON := jsonMode(cmd)
+	if asJSON {
+		if err := checkJSONFlags(cmd); err != nil {
+			return err
 		}
 	}
 
-	cfg, err := core.LoadConfig()
-	if err != nil {
+	if list, _ := cmd.Flags().GetBool("list-templates"); list {
+		return listTemplates()
+	}
+	if check, _ := cmd.Flags().GetBool("config-check"); check {
+		err := cli.CheckConfig(cmd.OutOrStdout())
+		cmd.SilenceUsage, cmd.SilenceErrors = err != nil, err != nil
 		return err
 	}
-	if style := viper.GetString("style"); style != "" {
-		cfg.DefaultStyle = style
+	if detailed, _ := cmd.Flags().GetBool("version-detailed"); detailed {
+		cli.WriteBuildInfo(Version, cmd.OutOrStdout())
+		return nil
+	}
+	if cmd.Flags().Changed("completion") {
+		shell, _ := cmd.Flags().GetString("completion")
+		return cli.WriteCompletion(cmd.Root(), shell, cmd.OutOrStdout())
+	}
+	if forget, _ := cmd.Flags().GetBool("history-clear"); forget {
+		return clearConversation()
+	}
+
+	continued, _ := cmd.Flags().GetBool("continue")
+	if continued {
+		for _, name := range []string{"follow", "compare"} {
+			if cmd.Flags().Changed(name) {
+				return &core.AppError{Msg: "--continue cannot be combined with --" + name}
+			}
+		}
 	}
 
-	client, err := mind.NewClientFromConfig(cfg)
+	follow, _ := cmd.Flags().GetBool("follow")
+	stdinPiped := false
+	if stat, err := os.Stdin.Stat(); err == nil {
+		stdinPiped = (stat.Mode() & os.ModeCharDevice) == 0
+	}
+	if follow {
+		if err := checkFollowFlags(cmd, stdinPiped); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := cli.LoadConfig()
 	if err != nil {
-		theme := ink.ThemeFrom(cfg.DefaultStyle)
+		if asJSON {
+			exitJSON(err, 1)
+		}
+		theme := ink.ThemeFrom(viper.GetString("style"))
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
 
-	systemPrompt := systemPromptTmpl
+	// Read piped stdin if available; --follow reads it incrementally instead.
+	var piped string
+	if stdinPiped && !follow {
+		if data, err := cli.ReadInput(os.Stdin, cfg, "piped input"); err == nil {
+			piped = core.NormalizeText(data)
+		}
+	}
+
+	var targets []compareTarget
+	if spec, _ := cmd.Flags().GetString("compare"); spec != "" {
+		if targets, err = parseCompareTargets(spec); err != nil {
+			return err
+		}
+		providers := make([]string, len(targets))
+		for i, t := range targets {
+			providers[i] = t.Provider
+		}
+		if err := cli.ResolveProviderKeys(&cfg, providers...); err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("tui") {
+			return &core.AppError{Msg: "--compare cannot be combined with --tui"}
+		}
+	}
+
+	var dirContext string
 	if !noContext {
 		cwd, err := os.Getwd()
 		if err == nil {
-			ctx := gatherContext(cwd)
-			if ctx != "" {
-				systemPrompt += "\n\nCurrent directory context:\n" + ctx
+			dirContext = gatherContext(cwd)
+		}
+	}
+
+	if follow {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if err := runFollow(cfg, question, dirContext, os.Stdin, interval); err != nil {
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	// No question and no template: askArgs only allows this on a terminal.
+	if question == "" && templateName == "" {
+		if targets != nil {
+			return &core.AppError{Msg: "--compare needs a question"}
+		}
+		if continued {
+			return &core.AppError{Msg: "--continue needs a question"}
+		}
+		return askInteractive(cmd, cfg, dirContext)
+	}
+
+	systemPrompt := systemPromptTmpl
+	if templateName != "" {
+		// Templates decide where input and context go.
+		question, err = renderTemplate(templateName, templateData{
+			Input:    piped,
+			Question: question,
+			Context:  dirContext,
+		})
+		if err != nil {
+			if asJSON {
+				exitJSON(err, 1)
 			}
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+	} else {
+		if piped != "" {
+			question = piped + "\n\n" + question
 		}
+		systemPrompt = mind.NewPromptBuilder(systemPrompt).
+			AddSection(dirContextTitle, dirContext).
+			String()
 	}
 
-	ch, err := client.Stream(context.Background(), systemPrompt, question)
-	if err != nil {
+	// The conversation keeps the question as asked, without the earlier
+	// exchanges --continue adds.
+	asked := question
+	if continued {
+		history, err := loadHistory(cfg)
+		if err != nil {
+			return err
+		}
+		if len(history) == 0 {
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			fmt.Fprintln(os.Stderr, theme.Muted("warning: no earlier question to continue from; starting a new conversation"))
+		}
+		question = withHistory(history, question)
+	}
+
+	// Ctrl-C ends the answer early instead of killing the process, so what
+	// was streamed so far is still saved with --save.
+	ctx, stop := cli.InterruptContext(context.Background())
+	defer stop()
+
+	var output string
+	if asJSON {
+		output = askJSON(ctx, cfg, systemPrompt, question, asked)
+		stop()
+		if continued {
+			recordTurn(cfg, asked, output)
+		}
+	} else if targets != nil {
+		var ok bool
+		output, ok = runCompare(ctx, cfg, targets, systemPrompt, question)
+		stop()
+		if !ok && ctx.Err() == nil {
+			os.Exit(1)
+		}
+	} else {
+		if err := cli.CheckPromptSize(cfg, systemPrompt, question); err != nil {
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+
+		client, err := cli.NewClient(cfg)
+		if err != nil {
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+
+		reqCtx, cancel := cli.RequestContext(ctx)
+		defer cancel()
+		tui, _ := cmd.Flags().GetBool("tui")
+		tui = useTUI(tui)
+		var spin *ink.Spinner
+		if !tui {
+			spin = cli.StartSpinner(ink.ThemeFrom(cfg.DefaultStyle), askSpinnerMessages...)
+		}
+		stream, err := client.Stream(reqCtx, systemPrompt, question)
+		if err != nil {
+			spin.Stop()
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			if ctx.Err() != nil {
+				cli.ExitInterrupt(theme)
+			}
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+
+		if tui {
+			if title == "" {
+				title = "template " + templateName
+			}
+			savePath, _ := cmd.Flags().GetString("save")
+			output, err = viewStream(cfg, stream, title, savePath, cancel)
+		} else {
+			printer := cli.NewStreamPrinter(os.Stdout)
+			printer.Spinner = spin
+			output, err = printer.PrintStream(stream.C)
+		}
+		stop()
+		if err != nil {
+			return err
+		}
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			theme := ink.ThemeFrom(cfg.DefaultStyle)
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+		if ctx.Err() == nil {
+			cli.WarnTruncated(cfg, stream)
+			cli.WarnEmpty(cfg, stream, output)
+			cli.PrintStats(cfg, stream)
+			cli.RecordTranscript("ask", cfg, systemPrompt, question, stream, output)
+			if continued {
+				recordTurn(cfg, asked, output)
+			}
+		}
+	}
+
+	if savePath, _ := cmd.Flags().GetString("save"); savePath != "" {
 		theme := ink.ThemeFrom(cfg.DefaultStyle)
+		if err := cli.SaveOutput(savePath, output); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+		cli.Note(theme, "saved to "+savePath)
+	}
+	if ctx.Err() != nil {
+		cli.ExitInterrupt(ink.ThemeFrom(cfg.DefaultStyle))
+	}
+	return nil
+}
+
+// askInteractive runs an interactive session and saves it with --save.
+func askInteractive(cmd *cobra.Command, cfg core.Config, dirContext string) error {
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	system := mind.NewPromptBuilder(systemPromptTmpl).
+		AddSection(dirContextTitle, dirContext).
+		String()
+	session, err := runInteractive(cfg, system)
+	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
+	if savePath, _ := cmd.Flags().GetString("save"); savePath != "" && session != "" {
+		if err := cli.SaveOutput(savePath, session); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+		cli.Note(theme, "saved to "+savePath)
+	}
+	return nil
+}
 
-	printer := ink.NewStreamPrinter(os.Stdout)
-	return printer.PrintStream(ch)
+// listTemplates prints the available prompt templates.
+func listTemplates() error {
+	tmpls, err := loadTemplates()
+	if err != nil {
+		return err
+	}
+	theme := ink.ThemeFrom(viper.GetString("style"))
+	tbl := theme.Table().Headers("NAME", "SOURCE")
+	for _, t := range tmpls {
+		tbl.Row(t.Name, t.Source)
+	}
+	cli.Page(tbl.String())
+	return nil
 }
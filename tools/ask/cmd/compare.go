@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+)
+
+// compareTarget is one provider:model pair given to --compare.
+type compareTarget struct {
+	Provider string
+	Model    string
+}
+
+// Label returns the target as written on the command line.
+func (t compareTarget) Label() string {
+	if t.Model == "" {
+		return t.Provider
+	}
+	return t.Provider + ":" + t.Model
+}
+
+// compareResult is the fully buffered answer of one target.
+type compareResult struct {
+	cfg    core.Config
+	stream *mind.Stream
+	output string
+	err    error
+}
+
+// parseCompareTargets parses a comma-separated provider[:model] list.
+func parseCompareTargets(spec string) ([]compareTarget, error) {
+	var targets []compareTarget
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		provider, model, _ := strings.Cut(part, ":")
+		if !mind.IsKnownProvider(provider) {
+			return nil, &core.AppError{
+				Msg: fmt.Sprintf("unknown provider %q in --compare (valid: %s)", provider, strings.Join(mind.KnownProviders(), ", ")),
+			}
+		}
+		targets = append(targets, compareTarget{Provider: provider, Model: model})
+	}
+	if len(targets) < 2 {
+		return nil, &core.AppError{Msg: "--compare needs at least two provider:model pairs"}
+	}
+	return targets, nil
+}
+
+// runCompare sends the same prompt to every target concurrently. Answers
+// are buffered and printed as labeled blocks in the order the targets were
+// given, each as soon as it and all blocks before it are complete. A failing
+// target is reported in its block without affecting the others; when ctx is
+// canceled, partial answers are shown as they are. It returns the combined
+// output and whether at least one target produced an answer.
+func runCompare(ctx context.Context, cfg core.Config, targets []compareTarget, system, user string) (string, bool) {
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+
+	results := make([]compareResult, len(targets))
+	done := make([]chan struct{}, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		done[i] = make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			results[i] = askTarget(ctx, cfg, t, system, user)
+		}()
+	}
+
+	var combined strings.Builder
+	ok := false
+	for i, t := range targets {
+		<-done[i]
+		r := results[i]
+
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(theme.Header(t.Label()))
+		// After Ctrl-C, show whatever part of an answer arrived.
+		interrupted := r.err != nil && ctx.Err() != nil && r.output != ""
+		if r.err != nil && !interrupted {
+			fmt.Fprintln(os.Stderr, theme.Error(r.err.Error()))
+			continue
+		}
+		ok = true
+
+		// Replay the buffered answer through the printer so it is styled
+		// the same way as a streamed one.
+		ch := make(chan string, 1)
+		ch <- strings.TrimRight(r.output, "\n")
+		close(ch)
+		if _, err := cli.NewStreamPrinter(os.Stdout).PrintStream(ch); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		}
+		if !interrupted {
+			cli.RecordTranscript("ask", r.cfg, system, user, r.stream, r.output)
+		}
+
+		fmt.Fprintf(&combined, "## %s\n\n%s\n\n", t.Label(), strings.TrimRight(r.output, "\n"))
+	}
+	wg.Wait()
+	return combined.String(), ok
+}
+
+// targetConfig returns the config t is asked with. A target on the
+// configured provider keeps cfg, with t's model when one is given. Any
+// other is built like a fallback, with its own key from api_keys and the
+// provider's default model unless t names one, so api_key never reaches
+// another vendor.
+func targetConfig(cfg core.Config, t compareTarget) core.Config {
+	if !strings.EqualFold(cfg.AIProvider, t.Provider) {
+		return mind.FallbackConfig(cfg, t.Label())
+	}
+	if t.Model != "" {
+		cfg.AIModel = t.Model
+	}
+	return cfg
+}
+
+// askTarget builds a client for t and buffers its whole answer.
+func askTarget(ctx context.Context, cfg core.Config, t compareTarget, system, user string) compareResult {
+	cfg = targetConfig(cfg, t)
+	r := compareResult{cfg: cfg}
+
+	if err := cli.CheckPromptSize(cfg, system, user); err != nil {
+		r.err = err
+		return r
+	}
+	client, err := cli.NewClient(cfg)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	ctx, cancel := cli.RequestContext(ctx)
+	defer cancel()
+	r.stream, err = client.Stream(ctx, system, user)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	var out strings.Builder
+	for chunk := range r.stream.C {
+		out.WriteString(chunk)
+	}
+	r.output = out.String()
+	if err := r.stream.Err(); err != nil {
+		r.err = err
+	} else if r.output == "" {
+		// Mid-stream failures only close the channel.
+		r.err = &core.AppError{Msg: t.Label() + " returned no response"}
+	}
+	return r
+}
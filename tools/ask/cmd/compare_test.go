@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+)
+
+// keyRecorder is a TLS server standing in for every provider. It records
+// the credentials each host received and refuses the request, so the
+// target fails fast without retries.
+type keyRecorder struct {
+	mu   sync.Mutex
+	keys map[string]string // host -> Authorization or x-api-key header
+}
+
+func (k *keyRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Authorization")
+	if key == "" {
+		key = r.Header.Get("x-api-key")
+	}
+	k.mu.Lock()
+	k.keys[r.Host] = key
+	k.mu.Unlock()
+	http.Error(w, `{"error":{"message":"test"}}`, http.StatusUnauthorized)
+}
+
+// tunnel starts an HTTP proxy that connects every CONNECT request to
+// backend, whatever host it names, so clients with fixed API URLs reach a
+// test server through http_proxy.
+func tunnel(t *testing.T, backend string) *httptest.Server {
+	t.Helper()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", backend)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			io.Copy(upstream, buf)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func TestCompareTargetKeys(t *testing.T) {
+	rec := &keyRecorder{keys: map[string]string{}}
+	backend := httptest.NewTLSServer(rec)
+	t.Cleanup(backend.Close)
+	proxy := tunnel(t, backend.Listener.Addr().String())
+
+	tests := []struct {
+		name    string
+		apiKeys map[string]string
+		env     string // $GLYPH_API_KEY_CLAUDE
+		want    map[string]string
+	}{
+		{
+			name:    "api_keys",
+			apiKeys: map[string]string{"claude": "claude-key"},
+			want:    map[string]string{"api.groq.com": "Bearer groq-key", "api.anthropic.com": "claude-key"},
+		},
+		{
+			name:    "environment",
+			apiKeys: map[string]string{"claude": "claude-key"},
+			env:     "env-key",
+			want:    map[string]string{"api.groq.com": "Bearer groq-key", "api.anthropic.com": "env-key"},
+		},
+		{
+			name: "no key for claude",
+			want: map[string]string{"api.groq.com": "Bearer groq-key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GLYPH_API_KEY_CLAUDE", tt.env)
+			rec.mu.Lock()
+			clear(rec.keys)
+			rec.mu.Unlock()
+
+			cfg := core.Config{
+				AIProvider:         "groq",
+				APIKey:             "groq-key",
+				APIKeys:            tt.apiKeys,
+				HTTPProxy:          proxy.URL,
+				InsecureSkipVerify: true,
+			}
+			targets, err := parseCompareTargets("groq,claude:claude-haiku-4-5")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cli.ResolveProviderKeys(&cfg, "groq", "claude"); err != nil {
+				t.Fatal(err)
+			}
+			for _, target := range targets {
+				if r := askTarget(t.Context(), cfg, target, "system", "user"); r.err == nil {
+					t.Errorf("%s: no error from a server that refuses every request", target.Label())
+				}
+			}
+
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			if len(rec.keys) != len(tt.want) {
+				t.Errorf("hosts reached = %q, want %q", rec.keys, tt.want)
+			}
+			for host, want := range tt.want {
+				if got := rec.keys[host]; got != want {
+					t.Errorf("%s got key %q, want %q", host, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTargetConfig(t *testing.T) {
+	cfg := core.Config{
+		AIProvider: "groq",
+		AIModel:    "llama-3.1-8b-instant",
+		APIKey:     "groq-key",
+		APIKeys:    map[string]string{"claude": "claude-key"},
+		Fallbacks:  []string{"ollama:llama3.2"},
+	}
+	tests := []struct {
+		target          compareTarget
+		provider, model string
+		key             string
+		keepsFallbacks  bool
+	}{
+		{compareTarget{"groq", ""}, "groq", "llama-3.1-8b-instant", "groq-key", true},
+		{compareTarget{"GROQ", "llama-3.3-70b-versatile"}, "groq", "llama-3.3-70b-versatile", "groq-key", true},
+		{compareTarget{"claude", ""}, "claude", "", "claude-key", false},
+		{compareTarget{"azure", "gpt-4o"}, "azure", "gpt-4o", "", false},
+	}
+	for _, tt := range tests {
+		got := targetConfig(cfg, tt.target)
+		if got.AIModel != tt.model || got.APIKey != tt.key || (len(got.Fallbacks) > 0) != tt.keepsFallbacks {
+			t.Errorf("%s: model %q, key %q, fallbacks %v; want %q, %q, fallbacks kept %v",
+				tt.target.Label(), got.AIModel, got.APIKey, got.Fallbacks, tt.model, tt.key, tt.keepsFallbacks)
+		}
+		if !strings.EqualFold(got.AIProvider, tt.provider) {
+			t.Errorf("%s: provider %q, want %q", tt.target.Label(), got.AIProvider, tt.provider)
+		}
+	}
+}
@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	git "github.com/reky0/glyph-git"
 )
 
 // gatherContext collects contextual information about the current directory.
@@ -56,15 +56,15 @@ func goContext(dir string) string {
 
 // gitContext returns the current branch and last commit subject.
 func gitContext(dir string) string {
-	branch, err := runGit(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	branch, err := git.Branch(dir)
 	if err != nil {
 		return ""
 	}
-	commit, err := runGit(dir, "log", "-1", "--pretty=%s")
+	commit, err := git.Log(dir, "-1", "--pretty=%s")
 	if err != nil {
-		return "Git branch: " + strings.TrimSpace(branch)
+		return "Git branch: " + branch
 	}
-	return "Git branch: " + strings.TrimSpace(branch) + "\nLast commit: " + strings.TrimSpace(commit)
+	return "Git branch: " + branch + "\nLast commit: " + strings.TrimSpace(string(commit))
 }
 
 // nodeContext reads package.json for project name and top-level dependencies.
@@ -99,15 +99,3 @@ func nodeContext(dir string) string {
 	}
 	return result
 }
-
-func runGit(dir string, args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = nil
-	if err := cmd.Run(); err != nil {
-		return "", err
-	}
-	return out.String(), nil
-}
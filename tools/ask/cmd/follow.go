@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+)
+
+// followMaxLines caps a batch so a burst of output is sent right away
+// instead of growing one prompt without bound.
+const followMaxLines = 500
+
+// followMaxLineBytes caps one line. The rest of a longer line, such as a
+// minified JSON payload, is dropped rather than ending the stream.
+const followMaxLineBytes = 1024 * 1024
+
+const followSystemPrompt = `You are monitoring a live stream of text, usually log output, for the user.
+Each message contains only the lines that arrived since the previous one.
+Answer the user's instruction for these lines in one to three short sentences.
+If nothing relevant happened, reply with exactly: Nothing notable.`
+
+// checkFollowFlags rejects --follow combinations that cannot work: it needs
+// piped input and produces many answers, so one-shot options do not apply.
+func checkFollowFlags(cmd *cobra.Command, stdinPiped bool) error {
+	if !stdinPiped {
+		return &core.AppError{Msg: "--follow needs piped input, e.g. tail -f app.log | ask --follow \"watch for errors\""}
+	}
+	for _, name := range []string{"compare", "template", "save", "tui"} {
+		if cmd.Flags().Changed(name) {
+			return &core.AppError{Msg: "--follow cannot be combined with --" + name}
+		}
+	}
+	if interval, _ := cmd.Flags().GetDuration("interval"); interval <= 0 {
+		return &core.AppError{Msg: "--interval must be positive"}
+	}
+	return nil
+}
+
+// runFollow reads in line by line and asks the model about new lines in
+// batches: a batch is sent interval after its first line arrives, or as soon
+// as it reaches followMaxLines. It returns when in ends or on Ctrl-C, and
+// reports an error that stopped the reading after sending what it read.
+func runFollow(cfg core.Config, question, dirContext string, in io.Reader, interval time.Duration) error {
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := cli.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	system := mind.NewPromptBuilder(followSystemPrompt).
+		AddSection(dirContextTitle, dirContext).
+		String()
+
+	lines := make(chan string, followMaxLines)
+	var readErr error // set before lines is closed
+	go func() {
+		defer close(lines)
+		readErr = readLines(in, lines)
+	}()
+
+	var batch []string
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		user := question + "\n\nNew lines:\n" + strings.Join(batch, "\n")
+		batch = batch[:0]
+
+		if !cli.Raw() {
+			cli.Note(theme, "── "+time.Now().Format(time.TimeOnly)+" ──")
+		}
+		if err := cli.CheckPromptSize(cfg, system, user); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			return
+		}
+		reqCtx, cancel := cli.RequestContext(ctx)
+		defer cancel()
+		spin := cli.StartSpinner(theme, askSpinnerMessages...)
+		stream, err := client.Stream(reqCtx, system, user)
+		if err != nil {
+			spin.Stop()
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			return
+		}
+		printer := cli.NewStreamPrinter(os.Stdout)
+		printer.Spinner = spin
+		output, err := printer.PrintStream(stream.C)
+		if err == nil {
+			err = stream.Err()
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			}
+			return
+		}
+		cli.WarnTruncated(cfg, stream)
+		cli.WarnEmpty(cfg, stream, output)
+		cli.PrintStats(cfg, stream)
+		cli.RecordTranscript("ask", cfg, system, user, stream, output)
+	}
+
+	if !cli.Raw() {
+		cli.Note(theme, "Following stdin — press Ctrl-C to exit.")
+	}
+	timer := time.NewTimer(interval)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				flush()
+				if readErr != nil {
+					return &core.AppError{Msg: "cannot read stdin", Err: readErr}
+				}
+				return nil
+			}
+			if len(batch) == 0 {
+				timer.Reset(interval)
+			}
+			batch = append(batch, line)
+			if len(batch) >= followMaxLines {
+				timer.Stop()
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// readLines sends each line of in to lines, cut to followMaxLineBytes with
+// "…" marking the cut, and returns the error that stopped the reading, or
+// nil at the end of in.
+func readLines(in io.Reader, lines chan<- string) error {
+	r := bufio.NewReaderSize(in, followMaxLineBytes)
+	for {
+		line, more, err := r.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		text := string(line)
+		if more {
+			// A cut may split a character; drop its leading bytes.
+			text = strings.ToValidUTF8(text, "") + "…"
+		}
+		for more && err == nil {
+			_, more, err = r.ReadLine()
+		}
+		lines <- text
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadLines(t *testing.T) {
+	errRead := errors.New("read failed")
+	long := strings.Repeat("x", followMaxLineBytes+10)
+	tests := []struct {
+		name    string
+		in      io.Reader
+		want    []string
+		wantErr error
+	}{
+		{"lines", strings.NewReader("a\r\nb\n\nc"), []string{"a", "b", "", "c"}, nil},
+		{
+			"long line",
+			strings.NewReader("before\n" + long + "\nafter\n"),
+			[]string{"before", long[:followMaxLineBytes] + "…", "after"},
+			nil,
+		},
+		{
+			"long line at the end",
+			strings.NewReader("before\n" + long),
+			[]string{"before", long[:followMaxLineBytes] + "…"},
+			nil,
+		},
+		{
+			"read error",
+			io.MultiReader(strings.NewReader("a\nb"), iotest.ErrReader(errRead)),
+			[]string{"a", "b"},
+			errRead,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make(chan string, 10)
+			err := readLines(tt.in, lines)
+			close(lines)
+			var got []string
+			for line := range lines {
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lines = %.40q, want %.40q", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
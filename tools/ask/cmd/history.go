@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"unicode/utf8"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	store "github.com/reky0/glyph-store"
+)
+
+// turn is one exchange of the conversation ask --continue builds on. Only
+// questions asked with --continue are stored; they are stored verbatim,
+// so the file is written with mode 0600.
+type turn struct {
+	store.Entry
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// size returns the characters of t counted against history_max_chars.
+func (t turn) size() int {
+	return utf8.RuneCountInString(t.Question) + utf8.RuneCountInString(t.Answer)
+}
+
+func openConversation() (*store.Store[turn], error) {
+	dir, err := core.NewPaths("ask").DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return store.NewStore[turn](filepath.Join(dir, "conversation.json")).WithPerm(0o600), nil
+}
+
+// recentTurns returns the newest of turns that fit the [ask] limits: at
+// most history_turns of them, with at most history_max_chars characters
+// between them. A newest turn longer than that on its own is shortened
+// rather than dropped, since a follow-up most likely refers to it.
+func recentTurns(turns []turn, cfg core.AskConfig) []turn {
+	var kept []turn
+	used := 0
+	for i := len(turns) - 1; i >= 0 && len(kept) < cfg.HistoryTurns; i-- {
+		t := turns[i]
+		if cfg.HistoryMaxChars > 0 && used+t.size() > cfg.HistoryMaxChars {
+			if len(kept) == 0 {
+				kept = append(kept, fitTurn(t, cfg.HistoryMaxChars))
+			}
+			break
+		}
+		used += t.size()
+		kept = append(kept, t)
+	}
+	slices.Reverse(kept)
+	return kept
+}
+
+// fitTurn shortens t to about n characters. The question keeps its end,
+// where the question asked follows any piped input, and the answer its
+// start; each gets at least half of n when it needs it.
+func fitTurn(t turn, n int) turn {
+	q, a := []rune(t.Question), []rune(t.Answer)
+	answerLen := min(len(a), n-min(len(q), n/2))
+	t.Question = keepEnd(q, n-answerLen)
+	t.Answer = keepStart(a, answerLen)
+	return t
+}
+
+// keepStart returns the first n characters of r, ending with "…" when
+// some were cut.
+func keepStart(r []rune, n int) string {
+	switch {
+	case len(r) <= n:
+		return string(r)
+	case n < 1:
+		return ""
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// keepEnd returns the last n characters of r, starting with "…" when some
+// were cut.
+func keepEnd(r []rune, n int) string {
+	switch {
+	case len(r) <= n:
+		return string(r)
+	case n < 1:
+		return ""
+	}
+	return "…" + string(r[len(r)-n+1:])
+}
+
+// loadHistory returns the exchanges --continue prefixes the question with.
+func loadHistory(cfg core.Config) ([]exchange, error) {
+	s, err := openConversation()
+	if err != nil {
+		return nil, err
+	}
+	turns, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	var history []exchange
+	for _, t := range recentTurns(turns, cfg.Ask) {
+		history = append(history, exchange{question: t.Question, answer: t.Answer})
+	}
+	return history, nil
+}
+
+// recordTurn adds a question answered with --continue to the conversation
+// and drops what no longer fits the [ask] limits. With history_turns = 0
+// nothing is kept. Like transcripts, a failure is reported as a warning on
+// stderr and never fails the command.
+func recordTurn(cfg core.Config, question, answer string) {
+	if cfg.Ask.HistoryTurns <= 0 || answer == "" {
+		return
+	}
+	s, err := openConversation()
+	if err == nil {
+		var turns []turn
+		if turns, err = s.Load(); err == nil {
+			turns = append(turns, turn{Entry: store.NewEntry(), Question: question, Answer: answer})
+			err = s.Save(recentTurns(turns, cfg.Ask))
+		}
+	}
+	if err != nil {
+		theme := ink.ThemeFrom(cfg.DefaultStyle)
+		fmt.Fprintln(os.Stderr, theme.Muted("warning: cannot record the question for --continue: "+err.Error()))
+	}
+}
+
+// clearConversation forgets the exchanges ask --continue builds on. It
+// backs --history-clear, the reserved-flag spelling of "history clear":
+// ask has no subcommands, so "ask history clear" would be a question.
+func clearConversation() error {
+	s, err := openConversation()
+	if err != nil {
+		return err
+	}
+	removed, err := s.DeleteFunc(func(turn) bool { return true })
+	if err != nil {
+		return err
+	}
+	if removed == 1 {
+		fmt.Println("forgot 1 exchange")
+	} else {
+		fmt.Printf("forgot %d exchanges\n", removed)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	core "github.com/reky0/glyph-core"
+)
+
+func TestRecentTurns(t *testing.T) {
+	turns := []turn{
+		{Question: "q1", Answer: "aaaa"},
+		{Question: "q2", Answer: "bbbb"},
+		{Question: "q3", Answer: "cccc"},
+	}
+	long := turn{Question: "explain this log", Answer: strings.Repeat("x", 40)}
+	tests := []struct {
+		name  string
+		turns []turn
+		cfg   core.AskConfig
+		want  []turn
+	}{
+		{"all fit", turns, core.AskConfig{HistoryTurns: 5, HistoryMaxChars: 100}, turns},
+		{"turn limit", turns, core.AskConfig{HistoryTurns: 2}, turns[1:]},
+		{"char limit", turns, core.AskConfig{HistoryTurns: 5, HistoryMaxChars: 13}, turns[1:]},
+		{"none kept", turns, core.AskConfig{HistoryTurns: 0, HistoryMaxChars: 100}, nil},
+		{
+			"newest too long",
+			append(turns[:1:1], long),
+			core.AskConfig{HistoryTurns: 5, HistoryMaxChars: 10},
+			[]turn{{Question: "… log", Answer: "xxxx…"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recentTurns(tt.turns, tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recentTurns = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitTurn(t *testing.T) {
+	tests := []struct {
+		question, answer string
+		n                int
+		want             turn
+	}{
+		// Both long: each gets half, the question keeping its end.
+		{"0123456789", "abcdefghij", 10, turn{Question: "…6789", Answer: "abcd…"}},
+		// A short question leaves the rest to the answer.
+		{"why?", "abcdefghijklmnopqrst", 10, turn{Question: "why?", Answer: "abcde…"}},
+		// A short answer leaves the rest to the question.
+		{"0123456789abcdefghij", "ok", 10, turn{Question: "…defghij", Answer: "ok"}},
+		// Counted in characters, not bytes.
+		{"äöüäöüäöüä", "ßßßßßßßßßß", 6, turn{Question: "…üä", Answer: "ßß…"}},
+	}
+	for _, tt := range tests {
+		got := fitTurn(turn{Question: tt.question, Answer: tt.answer}, tt.n)
+		if got != tt.want {
+			t.Errorf("fitTurn(%q, %q, %d) = %+v, want %+v", tt.question, tt.answer, tt.n, got, tt.want)
+		}
+		if got.size() > tt.n {
+			t.Errorf("fitTurn(%q, %q, %d) kept %d characters", tt.question, tt.answer, tt.n, got.size())
+		}
+	}
+}
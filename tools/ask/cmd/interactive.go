@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+)
+
+// Bracketed paste markers, which some terminals leave enabled after the
+// shell exits. They are dropped from typed input.
+const (
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+// exchange is one question and answer of an interactive session.
+type exchange struct {
+	question, answer string
+}
+
+// stdinIsTerminal reports whether stdin is a terminal rather than a pipe,
+// a file or the null device, which is also a character device.
+func stdinIsTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(stat, null)
+}
+
+// runInteractive reads questions from the terminal until an empty one and
+// streams an answer to each. A question may span several lines and ends
+// only with Ctrl-D, so pasted text with blank lines is sent as one
+// question. Earlier exchanges are included with each question so follow-ups
+// can refer to them. Ctrl-C stops the current answer and returns to the
+// prompt. It returns the session formatted for --save.
+func runInteractive(cfg core.Config, system string) (string, error) {
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	client, err := cli.NewClient(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	var history []exchange
+	var session strings.Builder
+	cli.Note(theme, "Type your question, then press Ctrl-D on an empty line to send it. Send an empty question to quit.")
+	for {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprint(os.Stderr, theme.Muted("> "))
+		question, err := readQuestion(in)
+		if err != nil {
+			return session.String(), err
+		}
+		if question == "" {
+			return session.String(), nil
+		}
+
+		user := withHistory(history, question)
+		if err := cli.CheckPromptSize(cfg, system, user); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			continue
+		}
+		answer, err := answerInteractive(cfg, client, system, user)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		}
+		if answer == "" {
+			continue
+		}
+		history = append(history, exchange{question: question, answer: answer})
+		fmt.Fprintf(&session, "> %s\n\n%s\n\n", strings.ReplaceAll(question, "\n", "\n> "), strings.TrimRight(answer, "\n"))
+	}
+}
+
+// readQuestion reads lines up to the next end of input (Ctrl-D) and returns
+// them trimmed. Reading from the terminal again afterwards works, since a
+// Ctrl-D only ends the current read.
+func readQuestion(in *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := in.ReadString('\n')
+		b.WriteString(line)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	q := strings.NewReplacer(pasteStart, "", pasteEnd, "").Replace(b.String())
+	return strings.TrimSpace(q), nil
+}
+
+// withHistory prefixes question with the earlier exchanges of the session.
+func withHistory(history []exchange, question string) string {
+	if len(history) == 0 {
+		return question
+	}
+	var b strings.Builder
+	b.WriteString("Conversation so far:\n\n")
+	for _, e := range history {
+		fmt.Fprintf(&b, "User: %s\n\nAssistant: %s\n\n", e.question, strings.TrimRight(e.answer, "\n"))
+	}
+	b.WriteString("New question:\n\n")
+	b.WriteString(question)
+	return b.String()
+}
+
+// answerInteractive streams one answer. After Ctrl-C it returns what was
+// streamed so far, with a nil error.
+func answerInteractive(cfg core.Config, client mind.Client, system, user string) (string, error) {
+	ctx, stop := cli.InterruptContext(context.Background())
+	defer stop()
+	reqCtx, cancel := cli.RequestContext(ctx)
+	defer cancel()
+
+	spin := cli.StartSpinner(ink.ThemeFrom(cfg.DefaultStyle), askSpinnerMessages...)
+	stream, err := client.Stream(reqCtx, system, user)
+	if err != nil {
+		spin.Stop()
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, ink.ThemeFrom(cfg.DefaultStyle).Muted("(interrupted)"))
+			return "", nil
+		}
+		return "", err
+	}
+	printer := cli.NewStreamPrinter(os.Stdout)
+	printer.Spinner = spin
+	output, err := printer.PrintStream(stream.C)
+	if err != nil {
+		return output, err
+	}
+	if ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, ink.ThemeFrom(cfg.DefaultStyle).Muted("(interrupted)"))
+		return output, nil
+	}
+	if err := stream.Err(); err != nil {
+		return output, err
+	}
+	cli.WarnTruncated(cfg, stream)
+	cli.WarnEmpty(cfg, stream, output)
+	cli.PrintStats(cfg, stream)
+	cli.RecordTranscript("ask", cfg, system, user, stream, output)
+	return output, nil
+}
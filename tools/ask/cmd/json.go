@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+)
+
+// jsonAnswer is what ask --json prints once the answer is complete.
+type jsonAnswer struct {
+	Question   string     `json:"question"`
+	Answer     string     `json:"answer"`
+	Provider   string     `json:"provider"`
+	Model      string     `json:"model"`
+	Tokens     mind.Usage `json:"tokens"`
+	DurationMS int64      `json:"duration_ms"`
+}
+
+// jsonFailure is what ask --json prints instead of an error message.
+type jsonFailure struct {
+	Error string `json:"error"`
+}
+
+// jsonMode reports whether --json was given.
+func jsonMode(cmd *cobra.Command) bool {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	return asJSON
+}
+
+// checkJSONFlags rejects flags that need a terminal or print more than one
+// answer, which --json cannot represent.
+func checkJSONFlags(cmd *cobra.Command) error {
+	for _, name := range []string{"follow", "tui", "compare", "list-templates"} {
+		if cmd.Flags().Changed(name) {
+			return &core.AppError{Msg: "--json cannot be combined with --" + name}
+		}
+	}
+	return nil
+}
+
+// jsonErrors prints err as {"error": ...} on stdout and exits with status
+// 1 when --json was given, so callers parse one format whatever happens.
+// Otherwise it returns err for cobra to report.
+func jsonErrors(cmd *cobra.Command, err error) error {
+	if err != nil && jsonMode(cmd) {
+		exitJSON(err, 1)
+	}
+	return err
+}
+
+// exitJSON prints err as {"error": ...} on stdout and exits with code.
+func exitJSON(err error, code int) {
+	writeJSON(jsonFailure{Error: err.Error()})
+	os.Exit(code)
+}
+
+// writeJSON prints v on stdout as one line of JSON.
+func writeJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
+
+// askJSON sends prompt, which is question with any earlier exchanges
+// --continue adds, and prints the answer with the question, provider,
+// model, token usage and duration as one JSON object, without streaming it.
+// Errors are printed as JSON too, and Ctrl-C prints {"error":"interrupted"}
+// and exits with status 130. It returns the answer for --save.
+func askJSON(ctx context.Context, cfg core.Config, systemPrompt, prompt, question string) string {
+	if err := cli.CheckPromptSize(cfg, systemPrompt, prompt); err != nil {
+		exitJSON(err, 1)
+	}
+	client, err := cli.NewClient(cfg)
+	if err != nil {
+		exitJSON(err, 1)
+	}
+
+	reqCtx, cancel := cli.RequestContext(ctx)
+	defer cancel()
+	start := time.Now()
+	stream, err := client.Stream(reqCtx, systemPrompt, prompt)
+	if err == nil {
+		var answer strings.Builder
+		for chunk := range stream.C {
+			answer.WriteString(chunk)
+		}
+		if err = stream.Err(); err == nil && ctx.Err() == nil {
+			provider := stream.Provider()
+			if provider == "" {
+				provider = cfg.AIProvider
+			}
+			output := answer.String()
+			writeJSON(jsonAnswer{
+				Question:   question,
+				Answer:     output,
+				Provider:   provider,
+				Model:      stream.Model(),
+				Tokens:     stream.Usage(),
+				DurationMS: time.Since(start).Milliseconds(),
+			})
+			cli.WarnTruncated(cfg, stream)
+			cli.WarnEmpty(cfg, stream, output)
+			cli.PrintStats(cfg, stream)
+			cli.RecordTranscript("ask", cfg, systemPrompt, prompt, stream, output)
+			return output
+		}
+	}
+	if ctx.Err() != nil {
+		exitJSON(&core.AppError{Msg: "interrupted"}, cli.ExitInterrupted)
+	}
+	exitJSON(err, 1)
+	return ""
+}
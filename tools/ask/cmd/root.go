@@ -3,20 +3,42 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	cli "github.com/reky0/glyph-cli"
+	ink "github.com/reky0/glyph-ink"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // Version is injected at build time via ldflags.
 var Version = "dev"
 
 var rootCmd = &cobra.Command{
-	Use:     "ask <question>",
+	Use:     "ask [question]",
 	Short:   "Ask a question to an AI with automatic directory context",
 	Version: Version,
-	Args:    cobra.MinimumNArgs(1),
-	RunE:    runAsk,
+	Args:    askArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return jsonErrors(cmd, runAsk(cmd, args))
+	},
+}
+
+// askArgs requires a question unless a template is given, since templates
+// can work from piped input alone, or a management flag such as
+// --list-templates or --config-check is set, or stdin is a terminal, where
+// ask prompts for questions instead. --json always needs a question or a
+// template.
+func askArgs(cmd *cobra.Command, args []string) error {
+	tmpl, _ := cmd.Flags().GetString("template")
+	list, _ := cmd.Flags().GetBool("list-templates")
+	forget, _ := cmd.Flags().GetBool("history-clear")
+	check, _ := cmd.Flags().GetBool("config-check")
+	detailed, _ := cmd.Flags().GetBool("version-detailed")
+	if tmpl != "" || list || forget || check || detailed || cmd.Flags().Changed("completion") || (stdinIsTerminal() && !jsonMode(cmd)) {
+		return nil
+	}
+	return jsonErrors(cmd, cobra.MinimumNArgs(1)(cmd, args))
 }
 
 func Execute() {
@@ -27,9 +49,32 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().String("style", "rounded", "Output style: ascii, rounded, minimal")
+	rootCmd.PersistentFlags().String("style", "auto", "Output style: "+strings.Join(ink.ThemeNames(), ", "))
+	rootCmd.PersistentFlags().String("save", "", "Also write the full response to this file")
 	rootCmd.Flags().Bool("no-context", false, "Skip automatic directory context injection")
-	if err := viper.BindPFlag("style", rootCmd.PersistentFlags().Lookup("style")); err != nil {
-		panic(fmt.Sprintf("failed to bind style flag: %v", err))
-	}
+	rootCmd.Flags().String("template", "", "Render the question through a named prompt template")
+	rootCmd.Flags().Bool("list-templates", false, "List available prompt templates and exit")
+	rootCmd.Flags().Bool("follow", false, "Read stdin as it grows and ask about new lines in batches")
+	rootCmd.Flags().Duration("interval", 10*time.Second, "With --follow, how long to collect new lines before asking")
+	rootCmd.Flags().Bool("tui", false, "Show the answer in a scrollable full-screen viewer (plain output when stdout is not a terminal)")
+	rootCmd.Flags().Bool("json", false, "Print the answer with its provider, model, token usage and duration as one JSON object")
+	rootCmd.Flags().Bool("continue", false, "Follow up on your latest --continue questions and answers, and remember this one (see history_turns under [ask])")
+	rootCmd.Flags().Bool("history-clear", false, "Forget the questions and answers --continue builds on, and exit")
+	rootCmd.Flags().String("compare", "", "Ask several providers at once, e.g. groq:llama-3.3-70b-versatile,claude:claude-sonnet-4-6")
+	cli.BindFlags(rootCmd, map[string]string{"style": "style"})
+	cli.AddAIFlags(rootCmd)
+	cli.AddRawFlag(rootCmd)
+	cli.AddQuietFlag(rootCmd)
+	cli.AddPagerFlag(rootCmd)
+	// ask takes a free-form question, so it has no subcommands: "ask config
+	// files in go" must ask. Tool management lives in reserved flags here
+	// and in the glyph binary's subcommands.
+	rootCmd.Flags().Bool("config-check", false, "Check the config file and suggest fixes, and exit (like glyph config check)")
+	rootCmd.Flags().Bool("version-detailed", false, "Print detailed build information, and exit (like glyph version)")
+	rootCmd.Flags().String("completion", "", "Print the shell completion script for bash, zsh, fish or powershell, and exit")
+}
+
+// Command returns the root command, for embedding in the glyph binary.
+func Command() *cobra.Command {
+	return rootCmd
 }
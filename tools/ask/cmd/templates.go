@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	core "github.com/reky0/glyph-core"
+)
+
+// builtinTemplates ship with ask; files in the templates directory with the
+// same name take precedence.
+var builtinTemplates = map[string]string{
+	"explain": `Explain what the following does, step by step.
+{{if .Question}}Focus on: {{.Question}}
+{{end}}
+{{.Input}}`,
+	"security": `Review the following for security issues (injection, secrets, unsafe input handling, permissions).
+List each issue with its severity and a suggested fix, or say that nothing stands out.
+{{if .Question}}Focus on: {{.Question}}
+{{end}}
+{{.Input}}`,
+	"tests": `Write tests for the following code using the project's conventions.
+{{if .Context}}Project context:
+{{.Context}}
+{{end}}{{if .Question}}Focus on: {{.Question}}
+{{end}}
+{{.Input}}`,
+}
+
+// promptTemplate is a named user-prompt template.
+type promptTemplate struct {
+	Name   string
+	Source string // "builtin" or the file path
+	Body   string
+}
+
+// templateData is the value templates are executed against.
+type templateData struct {
+	Input    string // piped stdin
+	Question string // positional arguments
+	Context  string // directory context, empty with --no-context
+}
+
+// templatesDir returns ~/.config/glyph/templates.
+func templatesDir() (string, error) {
+	dir, err := core.NewPaths("ask").ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// loadTemplates returns built-in templates merged with *.tmpl files from
+// the templates directory, sorted by name.
+func loadTemplates() ([]promptTemplate, error) {
+	byName := map[string]promptTemplate{}
+	for name, body := range builtinTemplates {
+		byName[name] = promptTemplate{Name: name, Source: "builtin", Body: body}
+	}
+
+	dir, err := templatesDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &core.AppError{Msg: "cannot read template " + path, Err: err}
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		byName[name] = promptTemplate{Name: name, Source: path, Body: string(data)}
+	}
+
+	tmpls := make([]promptTemplate, 0, len(byName))
+	for _, t := range byName {
+		tmpls = append(tmpls, t)
+	}
+	sort.Slice(tmpls, func(i, j int) bool { return tmpls[i].Name < tmpls[j].Name })
+	return tmpls, nil
+}
+
+// renderTemplate executes the named template with data.
+func renderTemplate(name string, data templateData) (string, error) {
+	tmpls, err := loadTemplates()
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tmpls {
+		if t.Name != name {
+			continue
+		}
+		tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(t.Body)
+		if err != nil {
+			return "", &core.AppError{Msg: fmt.Sprintf("invalid template %q", name), Err: err}
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, data); err != nil {
+			return "", &core.AppError{Msg: fmt.Sprintf("cannot render template %q", name), Err: err}
+		}
+		return out.String(), nil
+	}
+	return "", &core.AppError{
+		Msg: fmt.Sprintf("unknown template %q (see ask --list-templates)", name),
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+)
+
+// useTUI reports whether --tui can show the answer in a viewer: stdout
+// must be a terminal, and --raw asks for plain output.
+func useTUI(tui bool) bool {
+	if !tui || cli.Raw() {
+		return false
+	}
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// viewStream shows stream in a full-screen viewer titled title until the
+// user quits, and returns the text streamed. Quitting before the answer
+// is complete calls cancel to end the request. The viewer's s key saves
+// to savePath, or to a timestamped file in the current directory.
+func viewStream(cfg core.Config, stream *mind.Stream, title, savePath string, cancel context.CancelFunc) (string, error) {
+	viewer := ink.NewViewer(ink.ThemeFrom(cfg.DefaultStyle))
+	viewer.Title = title
+	viewer.SavePath = savePath
+	if viewer.SavePath == "" {
+		viewer.SavePath = time.Now().Format("ask-20060102-150405.md")
+	}
+
+	printer := cli.NewStreamPrinter(viewer)
+	var (
+		output   string
+		printErr error
+		done     = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		output, printErr = printer.PrintStream(stream.C)
+		viewer.Done(streamStatus(stream))
+	}()
+	runErr := viewer.Run()
+	cancel()
+	<-done
+	if runErr != nil {
+		return output, &core.AppError{Msg: "cannot show the viewer", Err: runErr}
+	}
+	return output, printErr
+}
+
+// streamStatus describes how stream ended, for the viewer's footer.
+func streamStatus(stream *mind.Stream) string {
+	if err := stream.Err(); err != nil {
+		return "failed: " + err.Error()
+	}
+	if u := stream.Usage(); u.OutputTokens > 0 {
+		return fmt.Sprintf("done · %d tokens in, %d out", u.InputTokens, u.OutputTokens)
+	}
+	return "done"
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	git "github.com/reky0/glyph-git"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+)
+
+// rangeCommit is one commit of a --each range.
+type rangeCommit struct {
+	hash    string
+	subject string
+}
+
+// label returns the short hash and subject, for headers.
+func (c rangeCommit) label() string {
+	return shortHash(c.hash) + " " + c.subject
+}
+
+// explainEach explains every commit of rev, oldest first, each under a
+// header with its hash and subject. Commits are sent one at a time, so a
+// long range does not flood the provider. A failing commit is reported and
+// the rest still run; Ctrl-C stops after the current one. With --save, the
+// file gets every explanation, each under a "## <hash> <subject>" heading.
+func explainEach(cmd *cobra.Command, cfg core.Config, client mind.Client, theme ink.Theme, rev string) error {
+	commits, err := listCommits(rev)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	if len(commits) == 0 {
+		fmt.Println(theme.Muted("No commits in " + rev + "."))
+		return nil
+	}
+
+	ctx, stop := cli.InterruptContext(context.Background())
+	defer stop()
+
+	var combined strings.Builder
+	failed := 0
+	for i, c := range commits {
+		if ctx.Err() != nil {
+			break
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(theme.Header(c.label()))
+
+		diffOutput, err := getDiff(false, c.hash, false)
+		if err == nil {
+			showDiff(cmd, theme, diffOutput)
+			var output string
+			output, err = explainDiff(ctx, cfg, client, capDiff(cmd, theme, diffOutput))
+			if output != "" {
+				fmt.Fprintf(&combined, "## %s\n\n%s\n\n", c.label(), strings.TrimRight(output, "\n"))
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			failed++
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		}
+	}
+	stop()
+
+	if combined.Len() > 0 {
+		saveOutput(cmd, theme, strings.TrimRight(combined.String(), "\n"))
+	}
+	if ctx.Err() != nil {
+		cli.ExitInterrupt(theme)
+	}
+	if failed > 0 {
+		fmt.Fprintln(os.Stderr, theme.Error(fmt.Sprintf("%d of %d commits could not be explained", failed, len(commits))))
+		os.Exit(1)
+	}
+	return nil
+}
+
+// listCommits returns the commits of rev, a revision range such as
+// v1.2.0..HEAD, oldest first. Merge commits are skipped since git show
+// prints no diff for them.
+func listCommits(rev string) ([]rangeCommit, error) {
+	out, err := git.Log("", "--reverse", "--no-merges", "--format=%H%x09%s", rev, "--")
+	if err != nil {
+		return nil, &core.AppError{Msg: "cannot list commits of " + rev, Err: err}
+	}
+	var commits []rangeCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		hash, subject, ok := strings.Cut(line, "\t")
+		if ok {
+			commits = append(commits, rangeCommit{hash: hash, subject: subject})
+		}
+	}
+	return commits, nil
+}
+
+// shortHash abbreviates a full commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
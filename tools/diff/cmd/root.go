@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"strings"
+	"unicode/utf8"
 
+	cli "github.com/reky0/glyph-cli"
 	core "github.com/reky0/glyph-core"
+	"github.com/reky0/glyph-diff/internal/unidiff"
+	git "github.com/reky0/glyph-git"
 	ink "github.com/reky0/glyph-ink"
 	mind "github.com/reky0/glyph-mind"
 	"github.com/spf13/cobra"
@@ -17,6 +22,11 @@ import (
 // Version is injected at build time via ldflags.
 var Version = "dev"
 
+// defaultMaxChars is the default of --max-chars: about 50k tokens, more
+// than any change a person would review, but short of what a vendored
+// directory or a regenerated lockfile can add up to.
+const defaultMaxChars = 200_000
+
 const diffSystemPrompt = `You are a code reviewer. Summarize what this diff does in plain language.
 List the most important changes as a short bullet list.
 End with one line flagging any potential issue if you see one, or "Looks clean." if not.`
@@ -36,12 +46,22 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().String("style", "rounded", "Output style: ascii, rounded, minimal")
+	rootCmd.PersistentFlags().String("style", "auto", "Output style: "+strings.Join(ink.ThemeNames(), ", "))
+	rootCmd.PersistentFlags().String("save", "", "Also write the full explanation to this file")
 	rootCmd.Flags().Bool("staged", false, "Diff staged changes (git diff --cached)")
 	rootCmd.Flags().String("commit", "", "Explain a specific commit (git show <hash>)")
-	if err := viper.BindPFlag("style", rootCmd.PersistentFlags().Lookup("style")); err != nil {
-		panic(fmt.Sprintf("failed to bind style flag: %v", err))
-	}
+	rootCmd.Flags().String("each", "", "Explain each commit of a range separately, e.g. v1.2.0..HEAD")
+	rootCmd.Flags().Bool("watch", false, "Re-run the explanation whenever the working tree changes")
+	rootCmd.Flags().Bool("include-untracked", false, "Also send new files that git does not track yet")
+	rootCmd.Flags().Bool("show-diff", false, "Print the diff itself before the explanation")
+	rootCmd.Flags().Int("max-chars", defaultMaxChars, "Send at most this many characters of the diff (0 means no limit)")
+	cli.BindFlags(rootCmd, map[string]string{"style": "style"})
+	cli.AddAIFlags(rootCmd)
+	cli.AddRawFlag(rootCmd)
+	cli.AddQuietFlag(rootCmd)
+	rootCmd.AddCommand(cli.VersionCommand(Version), cli.CompletionCommand(), cli.ThemeCommand(), cli.ConfigCommand())
+	rootCmd.AddCommand(cli.ModelsCommand())
+	rootCmd.AddCommand(cli.HistoryCommand())
 }
 
 func runDiff(cmd *cobra.Command, args []string) error {
@@ -49,68 +69,309 @@ func runDiff(cmd *cobra.Command, args []string) error {
 
 	staged, _ := cmd.Flags().GetBool("staged")
 	commitHash, _ := cmd.Flags().GetString("commit")
+	watch, _ := cmd.Flags().GetBool("watch")
+	untracked, _ := cmd.Flags().GetBool("include-untracked")
+	each, _ := cmd.Flags().GetString("each")
+
+	if untracked && (staged || commitHash != "") {
+		fmt.Fprintln(os.Stderr, theme.Error("--include-untracked cannot be combined with --staged or --commit"))
+		os.Exit(1)
+	}
+	if each != "" {
+		if staged || commitHash != "" || watch || untracked {
+			fmt.Fprintln(os.Stderr, theme.Error("--each cannot be combined with --staged, --commit, --watch or --include-untracked"))
+			os.Exit(1)
+		}
+		requireRepo(theme)
+		cfg, client := newClient(theme)
+		return explainEach(cmd, cfg, client, theme, each)
+	}
+	if watch {
+		if commitHash != "" {
+			fmt.Fprintln(os.Stderr, theme.Error("--watch cannot be combined with --commit"))
+			os.Exit(1)
+		}
+		requireRepo(theme)
+		cfg, client := newClient(theme)
+		return watchDiff(context.Background(), cmd, cfg, client, theme, staged, untracked)
+	}
 
-	diffOutput, err := getDiff(staged, commitHash)
+	// A diff piped on stdin (from any VCS or a saved patch) takes the place
+	// of running git.
+	diffOutput, err := readPipedDiff()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
+	if diffOutput != nil && (staged || commitHash != "" || untracked) {
+		fmt.Fprintln(os.Stderr, theme.Error("a piped diff cannot be combined with --staged, --commit or --include-untracked"))
+		os.Exit(1)
+	}
+	if diffOutput == nil {
+		requireRepo(theme)
+		diffOutput, err = getDiff(staged, commitHash, untracked)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+	}
 	if len(bytes.TrimSpace(diffOutput)) == 0 {
 		fmt.Println(theme.Muted("No changes found."))
 		return nil
 	}
 
-	cfg, err := core.LoadConfig()
+	cfg, client := newClient(theme)
+	showDiff(cmd, theme, diffOutput)
+
+	// Ctrl-C ends the explanation early; the part already written is still
+	// saved.
+	ctx, stop := cli.InterruptContext(context.Background())
+	defer stop()
+	output, err := explainDiff(ctx, cfg, client, capDiff(cmd, theme, diffOutput))
+	stop()
+	if ctx.Err() != nil {
+		if output != "" {
+			saveOutput(cmd, theme, output)
+		}
+		cli.ExitInterrupt(theme)
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
-	if style := viper.GetString("style"); style != "" {
-		cfg.DefaultStyle = style
+	saveOutput(cmd, theme, output)
+	return nil
+}
+
+// showDiff prints diffOutput in color when --show-diff is set.
+func showDiff(cmd *cobra.Command, theme ink.Theme, diffOutput []byte) {
+	if show, _ := cmd.Flags().GetBool("show-diff"); show {
+		fmt.Println(strings.TrimRight(theme.RenderDiff(string(diffOutput)), "\n"))
+		fmt.Println()
+	}
+}
+
+// maxOmittedNames is how many left-out files the note capDiff adds names
+// before summing up the rest.
+const maxOmittedNames = 10
+
+// capDiff returns diffOutput cut to --max-chars characters by cutDiff, with
+// a line saying what was cut, and warns on stderr when it cuts. --show-diff
+// still prints the whole diff.
+func capDiff(cmd *cobra.Command, theme ink.Theme, diffOutput []byte) []byte {
+	maxChars, _ := cmd.Flags().GetInt("max-chars")
+	total := utf8.RuneCount(diffOutput)
+	if maxChars <= 0 || total <= maxChars {
+		return diffOutput
+	}
+	shown, omitted := cutDiff(diffOutput, maxChars)
+	if len(omitted) > 0 {
+		files := "files are"
+		if len(omitted) == 1 {
+			files = "file is"
+		}
+		fmt.Fprintln(os.Stderr, theme.Muted(fmt.Sprintf(
+			"warning: the diff has %d characters; %d %s left out to send at most %d (raise --max-chars to send them)", total, len(omitted), files, maxChars)))
+	} else {
+		fmt.Fprintln(os.Stderr, theme.Muted(fmt.Sprintf(
+			"warning: the diff has %d characters; only the first %d are sent (raise --max-chars to send more)", total, maxChars)))
 	}
+	shown = shown[:len(shown):len(shown)]
+	if len(shown) > 0 && shown[len(shown)-1] != '\n' {
+		shown = append(shown, '\n')
+	}
+	note := fmt.Sprintf("(diff truncated: showing %d of %d characters", utf8.RuneCount(shown), total)
+	if len(omitted) > 0 {
+		note += "; left out: " + omittedNames(omitted)
+	}
+	return append(shown, note+")\n"...)
+}
 
-	client, err := mind.NewClientFromConfig(cfg)
+// cutDiff returns at most maxChars characters of diffOutput. Whole files
+// are kept in order, skipping those that no longer fit, so one huge file
+// such as a regenerated lockfile does not push out the rest; the skipped
+// files are returned. Text between files, such as a commit message, goes
+// with the file after it. When no file fits, or diffOutput is not a
+// unified diff, it is cut on the last line boundary instead.
+func cutDiff(diffOutput []byte, maxChars int) ([]byte, []unidiff.FileDiff) {
+	files := unidiff.Parse(diffOutput)
+	var (
+		shown   []byte
+		omitted []unidiff.FileDiff
+		prev    int
+	)
+	left := maxChars
+	for _, f := range files {
+		piece := diffOutput[prev:f.End]
+		prev = f.End
+		if n := utf8.RuneCount(piece); n <= left {
+			shown = append(shown, piece...)
+			left -= n
+		} else {
+			omitted = append(omitted, f)
+		}
+	}
+	if len(omitted) == len(files) {
+		return cutLines(diffOutput, maxChars), nil
+	}
+	if rest := diffOutput[prev:]; utf8.RuneCount(rest) <= left {
+		shown = append(shown, rest...)
+	}
+	return shown, omitted
+}
+
+// cutLines returns the first maxChars characters of s, dropping the last
+// line if it is cut short.
+func cutLines(s []byte, maxChars int) []byte {
+	cut := 0
+	for range maxChars {
+		_, size := utf8.DecodeRune(s[cut:])
+		cut += size
+	}
+	shown := s[:cut]
+	if i := bytes.LastIndexByte(shown, '\n'); i >= 0 {
+		shown = shown[:i+1]
+	}
+	return shown
+}
+
+// omittedNames lists files with their added and removed line counts, for
+// the note on a cut diff.
+func omittedNames(files []unidiff.FileDiff) string {
+	var names []string
+	for i, f := range files {
+		if i == maxOmittedNames {
+			names = append(names, fmt.Sprintf("and %d more", len(files)-i))
+			break
+		}
+		if f.Binary {
+			names = append(names, f.Path()+" (binary)")
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s (+%d -%d)", f.Path(), f.LinesAdded, f.LinesRemoved))
+	}
+	return strings.Join(names, ", ")
+}
+
+// saveOutput writes output to the --save path, if one was given.
+func saveOutput(cmd *cobra.Command, theme ink.Theme, output string) {
+	savePath, _ := cmd.Flags().GetString("save")
+	if savePath == "" {
+		return
+	}
+	if err := cli.SaveOutput(savePath, output); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	cli.Note(theme, "saved to "+savePath)
+}
+
+// newClient loads the config and builds the AI client, exiting with a
+// themed error if either step fails.
+func newClient(theme ink.Theme) (core.Config, mind.Client) {
+	cfg, err := cli.LoadConfig()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
 
-	ch, err := client.Stream(context.Background(), diffSystemPrompt, string(diffOutput))
+	client, err := cli.NewClient(cfg)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
+	// Explaining a diff has no side effects, so a failed start is safe to
+	// retry.
+	return cfg, mind.WithRetry(client, cfg.Retries)
+}
+
+// explainDiff streams the model's explanation of diffOutput to stdout and
+// returns the full explanation. When ctx is canceled mid-answer it returns
+// the part already written along with the error.
+func explainDiff(ctx context.Context, cfg core.Config, client mind.Client, diffOutput []byte) (string, error) {
+	if err := cli.CheckPromptSize(cfg, diffSystemPrompt, string(diffOutput)); err != nil {
+		return "", err
+	}
+	ctx, cancel := cli.RequestContext(ctx)
+	defer cancel()
+	spin := cli.StartSpinner(ink.ThemeFrom(cfg.DefaultStyle), "reviewing diff…", "reading the changes…", "still reviewing…")
+	stream, err := client.Stream(ctx, diffSystemPrompt, string(diffOutput))
+	if err != nil {
+		spin.Stop()
+		return "", err
+	}
 
-	printer := ink.NewStreamPrinter(os.Stdout)
-	return printer.PrintStream(ch)
+	printer := cli.NewStreamPrinter(os.Stdout)
+	printer.Spinner = spin
+	output, err := printer.PrintStream(stream.C)
+	if err != nil {
+		return "", err
+	}
+	if err := stream.Err(); err != nil {
+		return output, err
+	}
+	cli.WarnTruncated(cfg, stream)
+	cli.WarnEmpty(cfg, stream, output)
+	cli.PrintStats(cfg, stream)
+	cli.RecordTranscript("diff", cfg, diffSystemPrompt, string(diffOutput), stream, output)
+	return output, nil
 }
 
-func getDiff(staged bool, commitHash string) ([]byte, error) {
-	var gitArgs []string
+// requireRepo exits with an error unless the current directory is in a
+// git working tree, so that case gets a clear message instead of git's.
+func requireRepo(theme ink.Theme) {
+	if err := git.RequireRepo(""); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
+	}
+}
+
+// readPipedDiff returns the diff piped on stdin, or nil when stdin is a
+// terminal or empty.
+func readPipedDiff() ([]byte, error) {
+	if isTTY(os.Stdin) {
+		return nil, nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, &core.AppError{Msg: "cannot read diff from stdin", Err: err}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// getDiff runs git for the requested diff. With untracked, new files that
+// git does not track yet are appended as added-file diffs.
+func getDiff(staged bool, commitHash string, untracked bool) ([]byte, error) {
+	var out []byte
+	var err error
 	switch {
 	case commitHash != "":
-		gitArgs = []string{"show", commitHash}
+		out, err = git.Show("", commitHash)
 	case staged:
-		gitArgs = []string{"diff", "--cached"}
+		out, err = git.Diff("", "--cached")
 	default:
-		gitArgs = []string{"diff", "HEAD"}
+		out, err = git.Diff("", "HEAD")
 	}
-
-	cmd := exec.Command("git", gitArgs...)
-	var out, errBuf bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errBuf
-
-	if err := cmd.Run(); err != nil {
-		msg := errBuf.String()
-		if msg == "" {
-			msg = err.Error()
-		}
-		// Check for non-git-repo error message.
+	if err != nil {
 		return nil, &core.AppError{
-			Msg: "git command failed — are you inside a git repository?",
-			Err: fmt.Errorf("%s", msg),
+			Msg: "git command failed",
+			Err: err,
 		}
 	}
-	return out.Bytes(), nil
+	if untracked {
+		extra, err := untrackedDiff()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, extra...)
+	}
+	return out, nil
+}
+
+// Command returns the root command, for embedding in the glyph binary.
+func Command() *cobra.Command {
+	return rootCmd
 }
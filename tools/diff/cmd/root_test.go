@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fileDiff returns a git diff adding path with n lines of "line".
+func fileDiff(path string, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", path, path, path, n)
+	for range n {
+		b.WriteString("+line\n")
+	}
+	return b.String()
+}
+
+func TestCutDiff(t *testing.T) {
+	small, other, huge := fileDiff("main.go", 2), fileDiff("util.go", 3), fileDiff("go.sum", 500)
+	hugeStart := huge[:strings.Index(huge, "+line")] + "+line\n+line\n"
+	message := "commit 1234567\nAuthor: Dev <dev@example.com>\n\n    Bump deps\n\n"
+	tests := []struct {
+		name        string
+		diff        string
+		maxChars    int
+		want        string
+		wantOmitted []string
+	}{
+		{
+			name:        "whole files in order",
+			diff:        small + other + huge,
+			maxChars:    len(small) + len(other) + 10,
+			want:        small + other,
+			wantOmitted: []string{"go.sum"},
+		},
+		{
+			name:        "a huge file does not push out later ones",
+			diff:        small + huge + other,
+			maxChars:    len(small) + len(other),
+			want:        small + other,
+			wantOmitted: []string{"go.sum"},
+		},
+		{
+			name:        "commit message goes with its file",
+			diff:        message + small + huge,
+			maxChars:    len(message) + len(small),
+			want:        message + small,
+			wantOmitted: []string{"go.sum"},
+		},
+		{
+			name:     "no file fits",
+			diff:     huge,
+			maxChars: len(hugeStart) + 3,
+			want:     hugeStart,
+		},
+		{
+			name:     "not a diff",
+			diff:     "first line\nsecond line\n",
+			maxChars: 15,
+			want:     "first line\n",
+		},
+		{
+			name:     "characters, not bytes",
+			diff:     "héllo wörld\nnext\n",
+			maxChars: 12,
+			want:     "héllo wörld\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shown, omitted := cutDiff([]byte(tt.diff), tt.maxChars)
+			if string(shown) != tt.want {
+				t.Errorf("shown =\n%s\nwant\n%s", shown, tt.want)
+			}
+			var names []string
+			for _, f := range omitted {
+				names = append(names, f.Path())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantOmitted, ",") {
+				t.Errorf("omitted = %q, want %q", names, tt.wantOmitted)
+			}
+		})
+	}
+}
+
+func TestOmittedNames(t *testing.T) {
+	var diff strings.Builder
+	for i := range maxOmittedNames + 2 {
+		diff.WriteString(fileDiff(fmt.Sprintf("f%02d.txt", i), 1))
+	}
+	diff.WriteString("diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n")
+	_, omitted := cutDiff([]byte(fileDiff("keep.txt", 1)+diff.String()), len(fileDiff("keep.txt", 1)))
+
+	got := omittedNames(omitted)
+	if !strings.HasPrefix(got, "f00.txt (+1 -0), f01.txt (+1 -0), ") || !strings.HasSuffix(got, "f09.txt (+1 -0), and 3 more") {
+		t.Errorf("omittedNames = %q", got)
+	}
+	if got := omittedNames(omitted[len(omitted)-1:]); got != "logo.png (binary)" {
+		t.Errorf("omittedNames(binary) = %q", got)
+	}
+}
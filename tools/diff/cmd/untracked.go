@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+	git "github.com/reky0/glyph-git"
+)
+
+// Limits on the untracked content sent with --include-untracked, so one
+// large generated file cannot crowd out the real diff.
+const (
+	untrackedFileLimit  = 16 * 1024 // bytes shown per file
+	untrackedTotalLimit = 64 * 1024 // bytes shown across all files
+)
+
+// binarySniffLen is how much of a file is searched for a NUL byte to tell
+// binary files, the same amount git looks at.
+const binarySniffLen = 8000
+
+// readHead returns the first limit bytes of the file at path and its full
+// size, without reading the rest of a large file.
+func readHead(path string, limit int) ([]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, int64(limit)))
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, max(info.Size(), int64(len(data))), nil
+}
+
+// untrackedDiff renders every untracked, non-ignored file as a "new file"
+// diff against /dev/null, the way git diff shows an added file. Each file
+// is cut at untrackedFileLimit and all of them at untrackedTotalLimit; the
+// files past the total are listed without content. Binary files are
+// listed without content too.
+func untrackedDiff() ([]byte, error) {
+	dir, err := git.RepoRoot("")
+	if err != nil {
+		return nil, &core.AppError{
+			Msg: "cannot find the repository root",
+			Err: err,
+		}
+	}
+	list, err := git.Run(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, &core.AppError{Msg: "cannot list untracked files", Err: err}
+	}
+
+	var out bytes.Buffer
+	budget := untrackedTotalLimit
+	for _, name := range strings.Split(string(list), "\x00") {
+		if name == "" {
+			continue
+		}
+		data, size, err := readHead(filepath.Join(dir, name), untrackedFileLimit)
+		if err != nil {
+			// Removed since it was listed, or unreadable: leave it out.
+			continue
+		}
+		fmt.Fprintf(&out, "diff --git a/%s b/%s\nnew file mode 100644\n", name, name)
+		switch {
+		case bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0:
+			fmt.Fprintf(&out, "Binary files /dev/null and b/%s differ\n", name)
+			continue
+		case budget <= 0:
+			fmt.Fprintf(&out, "(new file, %d bytes, content omitted)\n", size)
+			continue
+		}
+		writeNewFile(&out, name, data, size, min(untrackedFileLimit, budget))
+		budget -= len(data)
+	}
+	return out.Bytes(), nil
+}
+
+// writeNewFile writes the first limit bytes of data, the head of a file of
+// size bytes, as an added-file hunk, noting when the rest was cut.
+func writeNewFile(out *bytes.Buffer, name string, data []byte, size int64, limit int) {
+	shown := data[:min(len(data), limit)]
+	if int64(len(shown)) < size {
+		// End on a whole line.
+		if i := bytes.LastIndexByte(shown, '\n'); i >= 0 {
+			shown = shown[:i+1]
+		}
+	}
+	lines := strings.SplitAfter(string(shown), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		// An empty file has no hunk, as in git diff.
+		return
+	}
+
+	fmt.Fprintf(out, "--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", name, len(lines))
+	for _, line := range lines {
+		out.WriteString("+" + line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	if int64(len(shown)) < size {
+		fmt.Fprintf(out, "(new file truncated: showing %d of %d bytes)\n", len(shown), size)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUntrackedDiff(t *testing.T) {
+	big := strings.Repeat("0123456789abcdef\n", 6000) // 102000 bytes
+	root := gitRepo(t, map[string]string{
+		".gitignore": "*.log\n",
+		"a.txt":      "hello\nworld",
+		"b.txt":      big,
+		"c.bin":      "PNG\x00\x01\x02",
+		"d.txt":      big,
+		"e.txt":      big,
+		"f.txt":      big,
+		"g.txt":      big,
+		"app.log":    "ignored",
+	})
+	t.Chdir(root)
+
+	data, err := untrackedDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		".gitignore b/.gitignore\nnew file mode 100644\n--- /dev/null\n+++ b/.gitignore\n@@ -0,0 +1,1 @@\n+*.log\n",
+		"+++ b/a.txt\n@@ -0,0 +1,2 @@\n+hello\n+world\n\\ No newline at end of file\n",
+		"+0123456789abcdef\n(new file truncated: showing 16371 of 102000 bytes)\ndiff --git a/c.bin",
+		"Binary files /dev/null and b/c.bin differ\n",
+		// f.txt gets what is left of the total, which ends on a line too.
+		"(new file truncated: showing 16354 of 102000 bytes)\n",
+		// Nothing is left for g.txt.
+		"diff --git a/g.txt b/g.txt\nnew file mode 100644\n(new file, 102000 bytes, content omitted)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("untracked diff does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "app.log") {
+		t.Error("untracked diff lists an ignored file")
+	}
+}
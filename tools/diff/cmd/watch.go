@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	git "github.com/reky0/glyph-git"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+)
+
+// watchDebounce is how long the tree must stay quiet before a re-run.
+const watchDebounce = 500 * time.Millisecond
+
+// watchDiff explains the diff once, then again every time the working tree
+// changes, until the user presses Ctrl-C.
+// With --save, the file always holds the latest explanation.
+func watchDiff(ctx context.Context, cmd *cobra.Command, cfg core.Config, client mind.Client, theme ink.Theme, staged, untracked bool) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	root, err := git.RepoRoot("")
+	if err != nil {
+		return &core.AppError{
+			Msg: "cannot find the repository root",
+			Err: err,
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return &core.AppError{Msg: "cannot start file watcher", Err: err}
+	}
+	defer watcher.Close()
+
+	if err := watchTree(watcher, root, ignoredDirs(root)); err != nil {
+		return &core.AppError{Msg: "cannot watch working tree", Err: err}
+	}
+
+	savePath, _ := cmd.Flags().GetString("save")
+	savePath = watchPath(savePath)
+
+	// The diff last shown in full. Runs never overlap, so they share it
+	// without locking.
+	var shown []byte
+	run := func(ctx context.Context) {
+		diffOutput, err := getDiff(staged, "", untracked)
+		if err == nil && shown != nil && bytes.Equal(diffOutput, shown) {
+			// Saving a file unchanged, or undoing an edit, needs no
+			// new explanation.
+			return
+		}
+		if isTTY(os.Stdout) && !cli.Raw() {
+			fmt.Print("\033[H\033[2J")
+		}
+		switch {
+		case err != nil:
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		case len(bytes.TrimSpace(diffOutput)) == 0:
+			fmt.Println(theme.Muted("No changes found."))
+			shown = diffOutput
+		default:
+			showDiff(cmd, theme, diffOutput)
+			output, err := explainDiff(ctx, cfg, client, capDiff(cmd, theme, diffOutput))
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+				}
+				break
+			}
+			shown = diffOutput
+			saveOutput(cmd, theme, output)
+		}
+		if ctx.Err() == nil && !cli.Raw() {
+			cli.Note(theme, "\nWatching for changes — press Ctrl-C to exit.")
+		}
+	}
+
+	// Each run gets its own context. A change while a run is still
+	// explaining cancels it, as its diff is already out of date, and the
+	// next run starts once it has stopped printing.
+	cancelRun, runDone := context.CancelFunc(func() {}), make(chan struct{})
+	close(runDone)
+	start := func() {
+		cancelRun()
+		<-runDone
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		cancelRun, runDone = cancel, done
+		go func() {
+			defer close(done)
+			run(runCtx)
+		}()
+	}
+	defer func() {
+		cancelRun()
+		<-runDone
+	}()
+	start()
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !watchEvent(root, savePath, ev) {
+				continue
+			}
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if isIgnored(root, ev.Name) {
+						continue
+					}
+					_ = watchTree(watcher, ev.Name, ignoredDirs(root))
+				}
+			}
+			timer.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		case <-timer.C:
+			start()
+		}
+	}
+}
+
+// watchEvent reports whether ev may change the diff and so calls for a
+// new run. Permission changes, writes to savePath (the --save file, which
+// each run rewrites), editor swap and backup files, and files git ignores
+// do not.
+func watchEvent(root, savePath string, ev fsnotify.Event) bool {
+	if ev.Op == fsnotify.Chmod || ev.Name == savePath || editorTemp(filepath.Base(ev.Name)) {
+		return false
+	}
+	return !isIgnored(root, ev.Name)
+}
+
+// editorTemp reports whether name is a file editors write beside the one
+// being edited: vim swap files and its 4913 write test, emacs lock and
+// autosave files, and backups ending in "~".
+func editorTemp(name string) bool {
+	switch {
+	case name == "4913", strings.HasSuffix(name, "~"):
+		return true
+	case strings.HasPrefix(name, ".#"):
+		return true
+	case strings.HasPrefix(name, "#") && strings.HasSuffix(name, "#"):
+		return true
+	case strings.HasPrefix(name, "."):
+		ext := filepath.Ext(name)
+		return len(ext) == 4 && strings.HasPrefix(ext, ".sw")
+	}
+	return false
+}
+
+// watchPath returns path as the watcher reports it: absolute, with
+// symlinks in its directory resolved, as they are in the repository root.
+// An empty path stays empty.
+func watchPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(dir, filepath.Base(abs))
+	}
+	return abs
+}
+
+// watchTree adds dir and every directory below it to watcher, skipping
+// hidden directories such as .git whose churn is not a working-tree change,
+// and the directories in ignored, such as node_modules or build output.
+func watchTree(watcher *fsnotify.Watcher, dir string, ignored map[string]bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != dir && (strings.HasPrefix(d.Name(), ".") || ignored[path]) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// ignoredDirs returns the directories below root that git ignores, by
+// their full path. Only the topmost ignored directory is listed, as
+// walking stops there. If git fails, nothing is skipped.
+func ignoredDirs(root string) map[string]bool {
+	// ls-files also lists directories holding only ignored files, which
+	// check-ignore then leaves out: a new file there would be a change.
+	out, err := git.Run(root, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory")
+	if err != nil {
+		return nil
+	}
+	candidates := []string{"check-ignore", "--"}
+	for _, name := range strings.Split(string(out), "\x00") {
+		if strings.HasSuffix(name, "/") {
+			candidates = append(candidates, name)
+		}
+	}
+	// check-ignore fails when none of them is ignored.
+	out, _ = git.Run(root, candidates...)
+	dirs := map[string]bool{}
+	for _, name := range strings.Split(string(out), "\n") {
+		if name == "" {
+			continue
+		}
+		// Names with special characters come C-quoted.
+		if u, err := strconv.Unquote(name); err == nil && strings.HasPrefix(name, `"`) {
+			name = u
+		}
+		dirs[filepath.Join(root, filepath.FromSlash(name))] = true
+	}
+	return dirs
+}
+
+// isIgnored reports whether git ignores path, a file or directory in the
+// repository at root.
+func isIgnored(root, path string) bool {
+	_, err := git.Run(root, "check-ignore", "-q", "--", path)
+	return err == nil
+}
+
+// isTTY reports whether f is attached to a terminal.
+func isTTY(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && (stat.Mode()&os.ModeCharDevice) != 0
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// gitRepo creates a repository in a temp dir holding files, with their
+// parent directories, and returns its root.
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", root).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The real path, as git prints it, in case the temp dir is a symlink.
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestWatchTreeSkipsIgnored(t *testing.T) {
+	root := gitRepo(t, map[string]string{
+		".gitignore":                "build/\nnode_modules\n*.log\ncafé/\n",
+		"café/menu.txt":             "menu",
+		"main.go":                   "package main\n",
+		"build/app":                 "binary",
+		"build/cache/x":             "x",
+		"web/src/app.js":            "app",
+		"web/node_modules/a/a.js":   "a",
+		"logs/today.log":            "log",
+		".github/workflows/ci.yaml": "on: push",
+	})
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	if err := watchTree(watcher, root, ignoredDirs(root)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, path := range watcher.WatchList() {
+		rel, _ := filepath.Rel(root, path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	slices.Sort(got)
+	// logs holds only ignored files but is not ignored itself.
+	want := []string{".", "logs", "web", "web/src"}
+	if !slices.Equal(got, want) {
+		t.Errorf("watched %q, want %q", got, want)
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	root := gitRepo(t, map[string]string{
+		".gitignore": "dist/\n",
+		"src/a.go":   "package a\n",
+		"dist/a.js":  "a",
+	})
+	for path, want := range map[string]bool{"dist": true, "src": false, "dist/a.js": true, "src/a.go": false} {
+		if got := isIgnored(root, filepath.Join(root, path)); got != want {
+			t.Errorf("isIgnored(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestWatchEvent(t *testing.T) {
+	root := gitRepo(t, map[string]string{
+		".gitignore": "dist/\n*.log\n",
+		"main.go":    "package main\n",
+	})
+	savePath := filepath.Join(root, "review.md")
+	tests := []struct {
+		name string
+		op   fsnotify.Op
+		want bool
+	}{
+		{"main.go", fsnotify.Write, true},
+		{"new.go", fsnotify.Create, true},
+		{"old.go", fsnotify.Remove, true},
+		{"main.go", fsnotify.Chmod, false},
+		{"main.go", fsnotify.Write | fsnotify.Chmod, true},
+		{"review.md", fsnotify.Write, false},
+		{"review.md", fsnotify.Create, false},
+		{"notes.md", fsnotify.Write, true},
+		{"debug.log", fsnotify.Write, false},
+		{"dist/app.js", fsnotify.Create, false},
+		{".main.go.swp", fsnotify.Write, false},
+		{".main.go.swx", fsnotify.Create, false},
+		{"4913", fsnotify.Create, false},
+		{"main.go~", fsnotify.Create, false},
+		{".#main.go", fsnotify.Create, false},
+		{"#main.go#", fsnotify.Write, false},
+		{".gitignore", fsnotify.Write, true},
+		{".swiftlint.yml", fsnotify.Write, true},
+	}
+	for _, tt := range tests {
+		ev := fsnotify.Event{Name: filepath.Join(root, tt.name), Op: tt.op}
+		if got := watchEvent(root, savePath, ev); got != tt.want {
+			t.Errorf("watchEvent(%s %s) = %v, want %v", tt.op, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWatchPath(t *testing.T) {
+	root := gitRepo(t, nil)
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(root, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	t.Chdir(link)
+	if got, want := watchPath("review.md"), filepath.Join(root, "review.md"); got != want {
+		t.Errorf("watchPath(review.md) = %q, want %q", got, want)
+	}
+	if got := watchPath(""); got != "" {
+		t.Errorf("watchPath(\"\") = %q, want it empty", got)
+	}
+}
@@ -0,0 +1,298 @@
+// Package unidiff parses unified diffs as printed by git diff, git show and
+// diff -u into per-file summaries.
+package unidiff
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// FileDiff describes the changes to one file.
+type FileDiff struct {
+	// OldPath and NewPath are the file's paths before and after the change,
+	// without git's a/ and b/ prefixes. OldPath is empty for an added file
+	// and NewPath for a deleted one.
+	OldPath string
+	NewPath string
+
+	// Commit is the hash from the "commit <hash>" header of git show or
+	// git log -p output that precedes the file, if any.
+	Commit string
+
+	Added   bool // the file was created
+	Deleted bool // the file was removed
+	Renamed bool // the file was renamed or copied from OldPath
+	Binary  bool // the content is binary and has no hunks
+
+	Hunks []Hunk
+
+	// LinesAdded and LinesRemoved count the + and - lines over all hunks.
+	LinesAdded   int
+	LinesRemoved int
+
+	// Start and End are the byte offsets of the file's diff in the input,
+	// from its first header line to the end of its last line, so
+	// data[Start:End] is the diff of this file alone.
+	Start, End int
+}
+
+// Path returns the file's current path, or its old path if it was deleted.
+func (f FileDiff) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Hunk is one @@ section of a file diff.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+
+	// Section is the text after the closing @@, usually the enclosing
+	// function.
+	Section string
+
+	// Lines holds the hunk body, each line keeping its ' ', '+', '-' or '\'
+	// marker.
+	Lines []string
+}
+
+// Parse splits a unified diff into files. Text that is not part of a file
+// diff, such as commit messages, is skipped, so Parse never fails; input
+// without any diff yields nil.
+func Parse(data []byte) []FileDiff {
+	var (
+		files  []FileDiff
+		cur    *FileDiff
+		hunk   *Hunk
+		commit string
+
+		// Byte offsets of the current line and of the line after it.
+		start, next int
+
+		// Lines still expected in the current hunk, from its header.
+		oldLeft, newLeft int
+	)
+	startFile := func() {
+		files = append(files, FileDiff{Commit: commit, Start: start})
+		cur = &files[len(files)-1]
+		hunk = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	scanner.Split(scanRawLines)
+	for scanner.Scan() {
+		// The previous line belongs to the file still current after it.
+		if cur != nil {
+			cur.End = next
+		}
+		start, next = next, next+len(scanner.Bytes())
+		line := strings.TrimSuffix(strings.TrimSuffix(scanner.Text(), "\n"), "\r")
+
+		// Hunk bodies come first: a removed line can look like a header.
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				cur.LinesAdded++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				cur.LinesRemoved++
+				oldLeft--
+			case strings.HasPrefix(line, " "), line == "":
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, `\`):
+			default:
+				// A truncated hunk; parse the line as a header instead.
+				oldLeft, newLeft = 0, 0
+				goto header
+			}
+			hunk.Lines = append(hunk.Lines, line)
+			continue
+		}
+		if hunk != nil && strings.HasPrefix(line, `\`) {
+			// "\ No newline at end of file" after the last counted line.
+			hunk.Lines = append(hunk.Lines, line)
+			continue
+		}
+
+	header:
+		switch {
+		case strings.HasPrefix(line, "commit "):
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				commit = fields[1]
+			}
+			cur, hunk = nil, nil
+
+		case strings.HasPrefix(line, "diff --git "):
+			startFile()
+			cur.OldPath, cur.NewPath = splitGitHeader(strings.TrimPrefix(line, "diff --git "))
+
+		case strings.HasPrefix(line, "--- ") && (cur == nil || hunk != nil):
+			// A file without a "diff --git" line, as from diff -u.
+			startFile()
+			cur.OldPath = headerPath(line[4:], "a/")
+
+		case cur == nil:
+			// Commit message or other preamble.
+
+		case strings.HasPrefix(line, "--- "):
+			cur.OldPath = headerPath(line[4:], "a/")
+			if cur.OldPath == "" {
+				cur.Added = true
+			}
+
+		case strings.HasPrefix(line, "+++ "):
+			cur.NewPath = headerPath(line[4:], "b/")
+			if cur.NewPath == "" {
+				cur.Deleted = true
+			}
+
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Added = true
+			cur.OldPath = ""
+
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Deleted = true
+			cur.NewPath = ""
+
+		case strings.HasPrefix(line, "rename from "), strings.HasPrefix(line, "copy from "):
+			cur.Renamed = true
+			_, from, _ := strings.Cut(line, " from ")
+			cur.OldPath = unquote(from)
+
+		case strings.HasPrefix(line, "rename to "), strings.HasPrefix(line, "copy to "):
+			cur.Renamed = true
+			_, to, _ := strings.Cut(line, " to ")
+			cur.NewPath = unquote(to)
+
+		case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+			cur.Binary = true
+
+		case strings.HasPrefix(line, "@@ "):
+			h, ok := parseHunkHeader(line)
+			if !ok {
+				continue
+			}
+			cur.Hunks = append(cur.Hunks, h)
+			hunk = &cur.Hunks[len(cur.Hunks)-1]
+			oldLeft, newLeft = h.OldLines, h.NewLines
+		}
+	}
+	if cur != nil {
+		cur.End = next
+	}
+	return files
+}
+
+// scanRawLines is bufio.ScanLines without stripping line endings, so the
+// length of each token is the number of bytes it takes in the input.
+func scanRawLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// parseHunkHeader parses "@@ -a,b +c,d @@ section". A missing count means
+// one line.
+func parseHunkHeader(line string) (Hunk, bool) {
+	rest := strings.TrimPrefix(line, "@@ ")
+	ranges, section, ok := strings.Cut(rest, " @@")
+	if !ok {
+		return Hunk{}, false
+	}
+	oldRange, newRange, ok := strings.Cut(ranges, " ")
+	if !ok || !strings.HasPrefix(oldRange, "-") || !strings.HasPrefix(newRange, "+") {
+		return Hunk{}, false
+	}
+	var h Hunk
+	if h.OldStart, h.OldLines, ok = parseRange(oldRange[1:]); !ok {
+		return Hunk{}, false
+	}
+	if h.NewStart, h.NewLines, ok = parseRange(newRange[1:]); !ok {
+		return Hunk{}, false
+	}
+	h.Section = strings.TrimSpace(section)
+	return h, true
+}
+
+// parseRange parses "start,count" or "start".
+func parseRange(s string) (start, count int, ok bool) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// splitGitHeader splits the "a/old b/new" part of a "diff --git" line. The
+// split is ambiguous for unquoted paths containing " b/", which is why the
+// ---, +++ and rename lines override it when present.
+func splitGitHeader(s string) (oldPath, newPath string) {
+	if strings.HasPrefix(s, `"`) {
+		if end := closingQuote(s); end > 0 {
+			return stripPrefix(unquote(s[:end+1]), "a/"), stripPrefix(unquote(strings.TrimSpace(s[end+1:])), "b/")
+		}
+	}
+	if i := strings.Index(s, " b/"); i >= 0 {
+		return stripPrefix(s[:i], "a/"), stripPrefix(unquote(s[i+1:]), "b/")
+	}
+	return stripPrefix(s, "a/"), ""
+}
+
+// headerPath returns the path of a ---/+++ line without its prefix and any
+// trailing timestamp, or "" for /dev/null.
+func headerPath(s, prefix string) string {
+	if tab := strings.IndexByte(s, '\t'); tab >= 0 {
+		s = s[:tab]
+	}
+	s = unquote(strings.TrimSpace(s))
+	if s == "/dev/null" {
+		return ""
+	}
+	return stripPrefix(s, prefix)
+}
+
+func stripPrefix(path, prefix string) string {
+	return strings.TrimPrefix(path, prefix)
+}
+
+// unquote decodes a C-style quoted path as git prints names with special
+// characters; other paths are returned as is.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	return s
+}
+
+// closingQuote returns the index of the quote closing the one at s[0].
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
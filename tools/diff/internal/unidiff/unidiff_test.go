@@ -0,0 +1,178 @@
+package unidiff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// summary is the part of a FileDiff the fixture tests compare.
+type summary struct {
+	OldPath, NewPath string
+	Commit           string
+	Added, Deleted   bool
+	Renamed, Binary  bool
+	Hunks            int
+	Plus, Minus      int
+}
+
+func summarize(files []FileDiff) []summary {
+	var out []summary
+	for _, f := range files {
+		out = append(out, summary{
+			OldPath: f.OldPath, NewPath: f.NewPath, Commit: f.Commit,
+			Added: f.Added, Deleted: f.Deleted, Renamed: f.Renamed, Binary: f.Binary,
+			Hunks: len(f.Hunks), Plus: f.LinesAdded, Minus: f.LinesRemoved,
+		})
+	}
+	return out
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+const (
+	newer = "f1a52d7d28b0fec69e5ab8cbc088ef41dca40b70"
+	older = "9e44eafc7af3d7a9763cc78999b7da5670531058"
+)
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []summary
+	}{
+		{"git.diff", []summary{
+			{NewPath: "café.txt", Added: true, Hunks: 1, Plus: 1},
+			{NewPath: "logo.bin", Added: true, Binary: true},
+			{OldPath: "main.go", NewPath: "main.go", Hunks: 1, Plus: 3, Minus: 1},
+			{OldPath: "notes.txt", Deleted: true, Hunks: 1, Minus: 1},
+			{OldPath: "list.txt", NewPath: "numbers.txt", Renamed: true, Hunks: 1, Plus: 1},
+			{OldPath: "q.sql", NewPath: "q.sql", Hunks: 1, Minus: 1},
+			{NewPath: "with space.txt", Added: true, Hunks: 1, Plus: 1},
+		}},
+		{"log.diff", []summary{
+			{OldPath: "q.sql", NewPath: "q.sql", Commit: newer, Hunks: 1, Plus: 1},
+			{NewPath: "café.txt", Commit: older, Added: true, Hunks: 1, Plus: 1},
+			{NewPath: "logo.bin", Commit: older, Added: true, Binary: true},
+			{OldPath: "main.go", NewPath: "main.go", Commit: older, Hunks: 1, Plus: 3, Minus: 1},
+			{OldPath: "notes.txt", Commit: older, Deleted: true, Hunks: 1, Minus: 1},
+			{OldPath: "list.txt", NewPath: "numbers.txt", Commit: older, Renamed: true, Hunks: 1, Plus: 1},
+			{OldPath: "q.sql", NewPath: "q.sql", Commit: older, Hunks: 1, Minus: 1},
+			{NewPath: "with space.txt", Commit: older, Added: true, Hunks: 1, Plus: 1},
+		}},
+		{"plain.diff", []summary{
+			{OldPath: "old.txt", NewPath: "new.txt", Hunks: 1, Plus: 1, Minus: 1},
+		}},
+		{"crlf.diff", []summary{
+			{OldPath: "old.txt", NewPath: "new.txt", Hunks: 1, Plus: 1, Minus: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := summarize(Parse(readFixture(t, tt.fixture)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHunks(t *testing.T) {
+	files := Parse(readFixture(t, "git.diff"))
+
+	main := files[2].Hunks[0]
+	if main.OldStart != 1 || main.OldLines != 5 || main.NewStart != 1 || main.NewLines != 7 || main.Section != "" {
+		t.Errorf("main.go hunk = %+v", main)
+	}
+	if want := "+\tfmt.Println(\"hi\")"; main.Lines[6] != want {
+		t.Errorf("main.go line 6 = %q, want %q", main.Lines[6], want)
+	}
+
+	numbers := files[4].Hunks[0]
+	if numbers.Section != "four" {
+		t.Errorf("numbers.txt section = %q, want four", numbers.Section)
+	}
+	if last := numbers.Lines[len(numbers.Lines)-1]; last != `\ No newline at end of file` {
+		t.Errorf("numbers.txt last line = %q", last)
+	}
+
+	// "--- a SQL comment" is a removed line, not a file header.
+	if got := files[5].Hunks[0].Lines[0]; got != "--- a SQL comment" {
+		t.Errorf("q.sql first line = %q", got)
+	}
+}
+
+func TestParseOffsets(t *testing.T) {
+	for _, fixture := range []string{"git.diff", "log.diff", "plain.diff", "crlf.diff"} {
+		t.Run(fixture, func(t *testing.T) {
+			data := readFixture(t, fixture)
+			files := Parse(data)
+			for i, f := range files {
+				if f.Start > f.End || f.End > len(data) {
+					t.Fatalf("file %d spans %d:%d of %d bytes", i, f.Start, f.End, len(data))
+				}
+				if i > 0 && f.Start < files[i-1].End {
+					t.Errorf("file %d starts at %d, inside file %d ending at %d", i, f.Start, i-1, files[i-1].End)
+				}
+				// Each span parses back to the same file on its own.
+				alone := Parse(data[f.Start:f.End])
+				f.Commit, f.Start, f.End = "", 0, f.End-f.Start
+				if len(alone) != 1 || !reflect.DeepEqual(alone[0], f) {
+					t.Errorf("file %d alone parses as %+v, want %+v", i, alone, f)
+				}
+			}
+		})
+	}
+
+	// Without commit headers the files cover the whole diff.
+	data := readFixture(t, "git.diff")
+	var joined []byte
+	for _, f := range Parse(data) {
+		joined = append(joined, data[f.Start:f.End]...)
+	}
+	if !bytes.Equal(joined, data) {
+		t.Errorf("file spans do not cover git.diff")
+	}
+}
+
+func TestParseNotADiff(t *testing.T) {
+	for _, in := range []string{"", "hello\nworld\n", "commit abc\n\n    message only\n"} {
+		if files := Parse([]byte(in)); files != nil {
+			t.Errorf("Parse(%q) = %+v, want nil", in, files)
+		}
+	}
+}
+
+func TestParseTruncatedHunk(t *testing.T) {
+	// The hunk promises 3 lines but the next file starts after one.
+	in := strings.Join([]string{
+		"diff --git a/x b/x",
+		"--- a/x",
+		"+++ b/x",
+		"@@ -1,3 +1,3 @@",
+		"-old",
+		"diff --git a/y b/y",
+		"--- a/y",
+		"+++ b/y",
+		"@@ -1 +1 @@",
+		"+new",
+		"",
+	}, "\n")
+	got := summarize(Parse([]byte(in)))
+	want := []summary{
+		{OldPath: "x", NewPath: "x", Hunks: 1, Minus: 1},
+		{OldPath: "y", NewPath: "y", Hunks: 1, Plus: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	mind "github.com/reky0/glyph-mind"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// probePrompt is the tiny fixed request sent to each provider.
+const (
+	probeSystem = "You are a health check. Reply with the single word: ok"
+	probeUser   = "ping"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check and time each configured provider",
+	Long: `Send a tiny prompt to the configured provider and to each fallback, and
+show the time to the first token and to the full answer. A failing
+provider shows why, such as a rejected API key or an Ollama server that
+is not running. Exits with status 1 if any provider fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		cfg, err := cli.LoadConfig()
+		if err != nil {
+			return err
+		}
+		theme := ink.ThemeFrom(viper.GetString("style"))
+
+		tbl := theme.Table().Headers("PROVIDER", "MODEL", "STATUS", "FIRST TOKEN", "TOTAL")
+		failed := 0
+		for _, target := range probeTargets(cfg) {
+			r := probe(target, timeout)
+			status, first, total := theme.Success("reachable"), formatLatency(r.first), formatLatency(r.total)
+			if r.err != nil {
+				failed++
+				status, first, total = theme.Error(oneLine(r.err.Error(), 60)), "-", "-"
+			}
+			tbl.Row(target.AIProvider, mind.ResolveModel(target), status, first, total)
+		}
+		tbl.Render(cmd.OutOrStdout())
+
+		if failed > 0 {
+			fmt.Fprintln(os.Stderr, theme.Error(fmt.Sprintf("%d of %d providers failed", failed, len(cfg.Fallbacks)+1)))
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// probeTargets returns one config per provider to check: the configured
+// one, then each entry of fallbacks.
+func probeTargets(cfg core.Config) []core.Config {
+	primary := cfg
+	primary.Fallbacks = nil
+	targets := []core.Config{primary}
+	for _, spec := range cfg.Fallbacks {
+		targets = append(targets, mind.FallbackConfig(cfg, spec))
+	}
+	return targets
+}
+
+// probeResult is the outcome of one probe.
+type probeResult struct {
+	first time.Duration // time to the first chunk
+	total time.Duration // time to the end of the answer
+	err   error
+}
+
+// probe sends the probe prompt with cfg and times the answer.
+func probe(cfg core.Config, timeout time.Duration) probeResult {
+	// The probe checks the connection, not the answer's shape.
+	cfg.ResponseFormat, cfg.JSONSchema, cfg.Stop = "", "", nil
+	client, err := mind.NewClientFromConfig(cfg)
+	if err != nil {
+		return probeResult{err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	stream, err := client.Stream(ctx, probeSystem, probeUser)
+	if err != nil {
+		return probeResult{err: err}
+	}
+	var r probeResult
+	for range stream.C {
+		if r.first == 0 {
+			r.first = time.Since(start)
+		}
+	}
+	r.total = time.Since(start)
+	r.err = stream.Err()
+	return r
+}
+
+// formatLatency renders d in milliseconds below a second and in seconds
+// otherwise, or "-" when nothing arrived.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// oneLine collapses whitespace in s and truncates it to n runes.
+func oneLine(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return s
+}
+
+func init() {
+	doctorCmd.Flags().Duration("timeout", 30*time.Second, "Give up on a provider after this long")
+	rootCmd.AddCommand(doctorCmd)
+}
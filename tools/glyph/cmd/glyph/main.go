@@ -0,0 +1,7 @@
+package main
+
+import "github.com/reky0/glyph/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	ask "github.com/reky0/glyph-ask/cmd"
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	diff "github.com/reky0/glyph-diff/cmd"
+	ink "github.com/reky0/glyph-ink"
+	pin "github.com/reky0/glyph-pin/cmd"
+	stand "github.com/reky0/glyph-stand/cmd"
+	"github.com/spf13/cobra"
+)
+
+// Version is injected at build time via ldflags.
+var Version = "dev"
+
+var rootCmd = &cobra.Command{
+	Use:     "glyph",
+	Short:   "pin, ask, diff and stand in a single binary",
+	Version: Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if path, _ := cmd.Flags().GetString("config"); path != "" {
+			core.SetConfigPath(path)
+		}
+		return nil
+	},
+}
+
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	// Run this root's hooks as well as each tool's, which bind the tool's
+	// flags to viper.
+	cobra.EnableTraverseRunHooks = true
+
+	rootCmd.PersistentFlags().String("config", "", "Config file to use instead of ~/.config/glyph/config.toml")
+	rootCmd.PersistentFlags().String("style", "auto", "Output style: "+strings.Join(ink.ThemeNames(), ", "))
+	cli.BindFlags(rootCmd, map[string]string{"style": "style"})
+	cli.AddRawFlag(rootCmd)
+	cli.AddQuietFlag(rootCmd)
+	rootCmd.AddCommand(cli.VersionCommand(Version), cli.CompletionCommand(), cli.ThemeCommand(), cli.ConfigCommand())
+	rootCmd.AddCommand(cli.ModelsCommand(), cli.HistoryCommand())
+
+	// Each tool keeps its own --style and --raw, which shadow the ones
+	// above, so flags work the same as with the standalone binaries.
+	rootCmd.AddCommand(ask.Command(), diff.Command(), pin.Command(), stand.Command())
+}
@@ -0,0 +1,69 @@
+module github.com/reky0/glyph
+
+go 1.24
+
+require (
+	github.com/reky0/glyph-ask v0.0.0
+	github.com/reky0/glyph-cli v0.0.0
+	github.com/reky0/glyph-core v0.0.0
+	github.com/reky0/glyph-diff v0.0.0
+	github.com/reky0/glyph-ink v0.0.0
+	github.com/reky0/glyph-mind v0.0.0
+	github.com/reky0/glyph-pin v0.0.0
+	github.com/reky0/glyph-stand v0.0.0
+	github.com/spf13/cobra v1.9.1
+	github.com/spf13/viper v1.20.1
+)
+
+require (
+	github.com/BurntSushi/toml v1.4.0 // indirect
+	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
+	github.com/charmbracelet/bubbles v0.21.0 // indirect
+	github.com/charmbracelet/bubbletea v1.3.4 // indirect
+	github.com/charmbracelet/colorprofile v0.2.3-0.20250311203215-f60798e515dc // indirect
+	github.com/charmbracelet/lipgloss v1.1.0 // indirect
+	github.com/charmbracelet/x/ansi v0.8.0 // indirect
+	github.com/charmbracelet/x/cellbuf v0.0.13-0.20250311204145-2c3ea96c31dd // indirect
+	github.com/charmbracelet/x/term v0.2.1 // indirect
+	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f // indirect
+	github.com/fsnotify/fsnotify v1.8.0 // indirect
+	github.com/go-viper/mapstructure/v2 v2.2.1 // indirect
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-localereader v0.0.1 // indirect
+	github.com/mattn/go-runewidth v0.0.16 // indirect
+	github.com/muesli/ansi v0.0.0-20230316100256-276c6243b2f6 // indirect
+	github.com/muesli/cancelreader v0.2.2 // indirect
+	github.com/muesli/termenv v0.16.0 // indirect
+	github.com/pelletier/go-toml/v2 v2.2.3 // indirect
+	github.com/reky0/glyph-git v0.0.0 // indirect
+	github.com/reky0/glyph-store v0.0.0 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	github.com/sagikazarmark/locafero v0.7.0 // indirect
+	github.com/sourcegraph/conc v0.3.0 // indirect
+	github.com/spf13/afero v1.12.0 // indirect
+	github.com/spf13/cast v1.7.1 // indirect
+	github.com/spf13/pflag v1.0.6 // indirect
+	github.com/subosito/gotenv v1.6.0 // indirect
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+	go.uber.org/atomic v1.9.0 // indirect
+	go.uber.org/multierr v1.9.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/reky0/glyph-ask => ../ask
+	github.com/reky0/glyph-cli => ../../libs/glyph-cli
+	github.com/reky0/glyph-core => ../../libs/glyph-core
+	github.com/reky0/glyph-diff => ../diff
+	github.com/reky0/glyph-git => ../../libs/glyph-git
+	github.com/reky0/glyph-ink => ../../libs/glyph-ink
+	github.com/reky0/glyph-mind => ../../libs/glyph-mind
+	github.com/reky0/glyph-pin => ../pin
+	github.com/reky0/glyph-stand => ../stand
+	github.com/reky0/glyph-store => ../../libs/glyph-store
+)
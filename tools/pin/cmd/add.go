@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
 	store "github.com/reky0/glyph-store"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var addCmd = &cobra.Command{
@@ -13,10 +18,27 @@ var addCmd = &cobra.Command{
 	Short: "Save a new entry",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		theme := ink.ThemeFrom(viper.GetString("style"))
 		text := strings.Join(args, " ")
 		tag, _ := cmd.Flags().GetString("tag")
 		isURL, _ := cmd.Flags().GetBool("url")
 		isCmd, _ := cmd.Flags().GetBool("cmd")
+		force, _ := cmd.Flags().GetBool("force")
+		ttl, _ := cmd.Flags().GetString("ttl")
+		metaPairs, _ := cmd.Flags().GetStringArray("meta")
+		meta, err := parseMeta(metaPairs)
+		if err != nil {
+			return err
+		}
+		var expiresAt time.Time
+		if ttl != "" {
+			if expiresAt, err = core.ParseUntil(ttl); err != nil {
+				return err
+			}
+			if !expiresAt.After(time.Now()) {
+				return &core.AppError{Msg: fmt.Sprintf("invalid --ttl %q: it must be in the future", ttl)}
+			}
+		}
 
 		entryType := ""
 		switch {
@@ -28,29 +50,64 @@ var addCmd = &cobra.Command{
 			entryType = InferType(text)
 		}
 
+		entries, s, err := loadEntries()
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("tag") {
+			// pin works without a config file, so an unreadable one
+			// only means no default tag.
+			if cfg, err := core.LoadConfig(); err == nil {
+				tag = cfg.Pin.DefaultTag
+			}
+		} else if tag != "" {
+			if near, ok := core.Suggest(tag, usedTags(entries)); ok {
+				// Without a terminal to ask on, the tag is kept as given.
+				if use, err := ink.Confirm(theme, fmt.Sprintf("no tag %q yet; did you mean %q?", tag, near), true); err == nil && use {
+					tag = near
+				}
+			}
+		}
+
 		entry := PinEntry{
 			Entry: store.NewEntry(),
 			Text:  text,
 			Tag:   tag,
 			Type:  entryType,
-		}
+			Meta:  meta,
 
-		s, err := openStore()
-		if err != nil {
-			return err
+			ExpiresAt: expiresAt,
+		}
+		if dup, ok := findByText(entries, text); ok && !force {
+			again, err := ink.Confirm(theme, fmt.Sprintf("already pinned as %s; pin it again?", shortID(dup.ID)), false)
+			if errors.Is(err, ink.ErrNotTerminal) {
+				return &core.AppError{Msg: fmt.Sprintf("already pinned as %s; use --force to pin it again", shortID(dup.ID))}
+			}
+			if !again {
+				fmt.Printf("not pinned; %s already holds this text (use --force to pin anyway)\n", shortID(dup.ID))
+				return nil
+			}
 		}
 		if err := s.Append(entry); err != nil {
 			return err
 		}
 
-		fmt.Printf("pinned %s [%s]\n", shortID(entry.ID), entryType)
+		if expiresAt.IsZero() {
+			fmt.Printf("pinned %s [%s]\n", shortID(entry.ID), entryType)
+		} else {
+			fmt.Printf("pinned %s [%s], expires %s\n", shortID(entry.ID), entryType, expiresAt.Format("2006-01-02 15:04"))
+		}
 		return nil
 	},
 }
 
 func init() {
-	addCmd.Flags().String("tag", "", "Tag for the entry")
+	addCmd.Flags().String("tag", "", "Tag for the entry (default: default_tag under [pin] in the config)")
 	addCmd.Flags().Bool("url", false, "Mark entry as a URL")
 	addCmd.Flags().Bool("cmd", false, "Mark entry as a command")
+	addCmd.Flags().StringArray("meta", nil, "Attach a key=value field, e.g. project=glyph (repeatable)")
+	addCmd.Flags().String("ttl", "", "Expire the entry after this long, e.g. 7d, 2w, 12h, or on a date such as 2026-01-31")
+	addCmd.Flags().Bool("force", false, "Pin even if an entry with the same text exists")
+	_ = addCmd.RegisterFlagCompletionFunc("tag", completeTags)
 	rootCmd.AddCommand(addCmd)
 }
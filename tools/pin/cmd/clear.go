@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all entries, or those older than --older-than",
+	Long: `Remove every entry of the collection (see --collection), or with
+--older-than only the entries created before the given age or date, such
+as 90d, 2w, 12h, "3 months" or 2026-01-31. Asks for confirmation unless --yes is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		olderThan, _ := cmd.Flags().GetString("older-than")
+
+		var cutoff time.Time
+		if olderThan != "" {
+			var err error
+			if cutoff, err = core.ParseSince(olderThan); err != nil {
+				return err
+			}
+		}
+		matches := func(e PinEntry) bool {
+			return cutoff.IsZero() || e.CreatedAt.Before(cutoff)
+		}
+
+		entries, s, err := loadEntries()
+		if err != nil {
+			return err
+		}
+		kept, count := deleteEntries(entries, matches)
+		if count == 0 {
+			fmt.Println("nothing to remove")
+			return nil
+		}
+
+		if !yes {
+			question := fmt.Sprintf("remove all %d %s?", count, plural(count, "entry", "entries"))
+			if olderThan != "" {
+				question = fmt.Sprintf("remove %d %s older than %s?", count, plural(count, "entry", "entries"), olderThan)
+			}
+			ok, err := ink.Confirm(ink.ThemeFrom(viper.GetString("style")), question, false)
+			if errors.Is(err, ink.ErrNotTerminal) {
+				return &core.AppError{Msg: "refusing to clear without confirmation; pass --yes"}
+			}
+			if !ok {
+				fmt.Println("nothing removed")
+				return nil
+			}
+		}
+
+		if err := s.Save(kept); err != nil {
+			return err
+		}
+		fmt.Printf("removed %d %s\n", count, plural(count, "entry", "entries"))
+		return nil
+	},
+}
+
+// plural returns one when n is 1 and many otherwise.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+func init() {
+	clearCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	clearCmd.Flags().String("older-than", "", "Only remove entries older than this, e.g. 90d, 2w, 12h or 2026-01-31")
+	rootCmd.AddCommand(clearCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var collectionsCmd = &cobra.Command{
+	Use:   "collections",
+	Short: "List pin collections",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := core.NewPaths("pin").DataDir()
+		if err != nil {
+			return err
+		}
+		files, err := filepath.Glob(filepath.Join(dir, "pins*.json"))
+		if err != nil {
+			return err
+		}
+		// The default collection first, then the others by name.
+		sort.Slice(files, func(i, j int) bool {
+			bi, bj := filepath.Base(files[i]), filepath.Base(files[j])
+			if (bi == "pins.json") != (bj == "pins.json") {
+				return bi == "pins.json"
+			}
+			return bi < bj
+		})
+
+		theme := ink.ThemeFrom(viper.GetString("style"))
+		tbl := theme.Table().Headers("NAME", "ENTRIES", "FILE")
+		for _, file := range files {
+			base := filepath.Base(file)
+			var name string
+			switch {
+			case base == "pins.json":
+				name = "(default)"
+			case strings.HasPrefix(base, "pins-"):
+				name = strings.TrimSuffix(strings.TrimPrefix(base, "pins-"), ".json")
+				if !collectionName.MatchString(name) {
+					continue
+				}
+			default:
+				continue
+			}
+			tbl.Row(name, countEntries(file), file)
+		}
+		cli.Page(tbl.String())
+		return nil
+	},
+}
+
+// countEntries returns the number of pins in file as a string, or "?" when
+// it cannot be read, for example when it is encrypted with another key.
+func countEntries(file string) string {
+	entries, err := newPinStore(file).Load()
+	if err != nil {
+		return "?"
+	}
+	return strconv.Itoa(len(entries))
+}
+
+func init() {
+	rootCmd.AddCommand(collectionsCmd)
+}
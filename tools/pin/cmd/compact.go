@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var compactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Sort entries by creation date and rewrite the data file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		if err := s.Compact(func(a, b PinEntry) bool {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}); err != nil {
+			return err
+		}
+		fmt.Println("compacted")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compactCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// completeIDs suggests entry IDs for commands taking a single <id>. Each
+// suggestion is the short ID, or the full ID when the short one is shared
+// with another entry, described by the entry's text.
+func completeIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	entries, _, err := loadEntries()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	shared := make(map[string]int, len(entries))
+	for _, e := range entries {
+		shared[shortID(e.ID)]++
+	}
+	suggestions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		id := shortID(e.ID)
+		if shared[id] > 1 {
+			id = e.ID
+		}
+		suggestions = append(suggestions, id+"\t"+truncate(e.Text, 40))
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeIDPair is completeIDs for commands taking two <id>s, such as
+// link.
+func completeIDPair(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeIDs(cmd, nil, toComplete)
+}
+
+// completeTags suggests the tags already in use, for --tag flags.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries, _, err := loadEntries()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return usedTags(entries), cobra.ShellCompDirectiveNoFileComp
+}
@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
 	store "github.com/reky0/glyph-store"
+	"gopkg.in/yaml.v3"
 )
 
 // PinEntry is a single stored item.
@@ -12,9 +18,81 @@ type PinEntry struct {
 	store.Entry
 	Text string `json:"text"`
 	Tag  string `json:"tag"`
-	Type string `json:"type"` // url | cmd | note
+	Type string `json:"type"` // url | cmd | json | yaml | code | note
+
+	// Favorite entries are marked with a star and listed first.
+	Favorite bool `json:"favorite"`
+
+	// Meta holds free-form key=value fields such as source or project.
+	Meta map[string]string `json:"meta,omitempty"`
+
+	// ExpiresAt is when the entry expires, set by add --ttl. Expired
+	// entries are hidden from list and search until pin gc removes them.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+
+	// Related holds the IDs of the entries linked to this one with pin
+	// link. Links go both ways.
+	Related []string `json:"related,omitempty"`
+}
+
+// Expired reports whether e has an expiry that is not after now.
+func (e PinEntry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !e.ExpiresAt.After(now)
+}
+
+// parseMeta parses key=value pairs from --meta flags. Keys must be
+// non-empty; values may be empty.
+func parseMeta(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	meta := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, &core.AppError{Msg: fmt.Sprintf("invalid --meta %q: use key=value", pair)}
+		}
+		meta[key] = value
+	}
+	return meta, nil
 }
 
+// matchesMeta reports whether e has every key=value pair in filter.
+func matchesMeta(e PinEntry, filter map[string]string) bool {
+	for key, value := range filter {
+		if got, ok := e.Meta[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// typeColors maps each entry type to its badge color.
+var typeColors = map[string]string{
+	"url":  "#8BE9FD",
+	"cmd":  "#50FA7B",
+	"json": "#FFB86C",
+	"yaml": "#FFB86C",
+	"code": "#FF79C6",
+	"note": "#F1FA8C",
+}
+
+// entryTypes lists the valid entry types, for completion and help.
+var entryTypes = []string{"url", "cmd", "json", "yaml", "code", "note"}
+
+// typeBadge renders an entry type as a badge for tables.
+func typeBadge(theme ink.Theme, entryType string) string {
+	if entryType == "" {
+		return ""
+	}
+	return theme.BadgeColored(entryType, typeColors[entryType])
+}
+
+// maxParseBytes bounds the text InferType tries to parse as JSON or YAML,
+// so pinning a huge file stays cheap; longer text is never json or yaml.
+const maxParseBytes = 64 * 1024
+
 // InferType guesses the entry type from the text content.
 func InferType(text string) string {
 	if looksLikeURL(text) {
@@ -23,6 +101,17 @@ func InferType(text string) string {
 	if looksLikeCmd(text) {
 		return "cmd"
 	}
+	if looksLikeJSON(text) {
+		return "json"
+	}
+	// Code before YAML: "def f():" followed by an indented body is valid
+	// YAML too.
+	if looksLikeCode(text) {
+		return "code"
+	}
+	if looksLikeYAML(text) {
+		return "yaml"
+	}
 	return "note"
 }
 
@@ -46,6 +135,61 @@ func looksLikeCmd(s string) bool {
 	return false
 }
 
+// looksLikeJSON reports whether s is a JSON object or array.
+func looksLikeJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	if len(s) > maxParseBytes || s == "" || (s[0] != '{' && s[0] != '[') {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// looksLikeYAML reports whether s is a multi-line YAML mapping or sequence.
+// Single lines are left alone, since "note: call back" parses as a mapping.
+func looksLikeYAML(s string) bool {
+	s = strings.TrimSpace(s)
+	if len(s) > maxParseBytes || !strings.Contains(s, "\n") {
+		return false
+	}
+	var v any
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return false
+	}
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// codeMarkers start lines that are typical of source code.
+var codeMarkers = []string{
+	"package ", "import ", "from ", "func ", "def ", "fn ", "function ",
+	"class ", "#include", "public ", "const ", "let ", "var ", "use ", "<?php",
+}
+
+// looksLikeCode reports whether s starts with a shebang, or is several
+// lines with one of the first codeScanLines starting with a code marker.
+func looksLikeCode(s string) bool {
+	const codeScanLines = 20
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "#!") {
+		return true
+	}
+	lines := strings.SplitN(s, "\n", codeScanLines+1)
+	if len(lines) < 2 {
+		return false
+	}
+	for _, line := range lines[:min(len(lines), codeScanLines)] {
+		for _, marker := range codeMarkers {
+			if strings.HasPrefix(strings.TrimSpace(line), marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // shortID returns the first 8 characters of the entry ID.
 func shortID(id string) string {
 	if len(id) <= 8 {
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
+)
+
+const explainSystemPrompt = `You are reviewing a shell command before the user runs it.
+Explain what it does in plain language, step by step for pipelines.
+Then list anything destructive, irreversible or surprising it could do, or say "Nothing risky." if there is nothing.
+Be concise. No markdown headers.`
+
+// explainEntry prints text followed by the model's explanation of it. The
+// AI config is only loaded here, so pin works without a provider unless
+// --explain is used.
+func explainEntry(text string) error {
+	cfg, err := cli.LoadConfig()
+	if err != nil {
+		return err
+	}
+	theme := ink.ThemeFrom(cfg.DefaultStyle)
+	client, err := cli.NewClient(cfg)
+	if err != nil {
+		return &core.AppError{Msg: "--explain needs an AI provider; set ai_provider (and api_key) in the glyph config", Err: err}
+	}
+
+	fmt.Println(theme.Muted("$ " + text))
+	fmt.Println()
+
+	ctx, cancel := cli.RequestContext(context.Background())
+	defer cancel()
+	spin := cli.StartSpinner(theme, "explaining command…", "checking for risks…")
+	stream, err := client.Stream(ctx, explainSystemPrompt, text)
+	if err != nil {
+		spin.Stop()
+		return err
+	}
+	printer := cli.NewStreamPrinter(os.Stdout)
+	printer.Spinner = spin
+	output, err := printer.PrintStream(stream.C)
+	if err != nil {
+		return err
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	cli.WarnTruncated(cfg, stream)
+	cli.WarnEmpty(cfg, stream, output)
+	cli.PrintStats(cfg, stream)
+	cli.RecordTranscript("pin", cfg, explainSystemPrompt, text, stream, output)
+	return nil
+}
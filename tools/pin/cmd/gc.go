@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove expired entries",
+	Long: `Remove the entries of the collection (see --collection) whose expiry,
+set with add --ttl, has passed. list and search already hide them; gc
+deletes them from the file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, s, err := loadEntries()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		kept, removed := deleteEntries(entries, func(e PinEntry) bool { return e.Expired(now) })
+		if removed == 0 {
+			fmt.Println("no expired entries")
+			return nil
+		}
+		if err := s.Save(kept); err != nil {
+			return err
+		}
+		fmt.Printf("removed %d expired %s\n", removed, plural(removed, "entry", "entries"))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+}
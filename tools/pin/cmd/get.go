@@ -10,20 +10,26 @@ var getCmd = &cobra.Command{
 	Use:   "get <id>",
 	Short: "Print raw text of an entry",
 	Args:  cobra.ExactArgs(1),
+
+	ValidArgsFunction: completeIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		entries, _, err := loadEntries()
+		s, err := openStore()
 		if err != nil {
 			return err
 		}
-		entry, _, err := findByID(entries, args[0])
+		entry, err := lookupEntry(s, args[0])
 		if err != nil {
 			return err
 		}
+		if explain, _ := cmd.Flags().GetBool("explain"); explain {
+			return explainEntry(entry.Text)
+		}
 		fmt.Print(entry.Text)
 		return nil
 	},
 }
 
 func init() {
+	getCmd.Flags().Bool("explain", false, "Ask the configured AI provider what the entry (usually a command) does before you run it")
 	rootCmd.AddCommand(getCmd)
 }
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	core "github.com/reky0/glyph-core"
+	store "github.com/reky0/glyph-store"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add the entries of another pins file",
+	Long: `Add the entries of a pins file, such as another collection's
+pins-work.json or a copy from another machine, to the collection (see
+--collection). An encrypted file is read with GLYPH_PIN_KEY. Entries whose
+id or text is already pinned are skipped, so importing the same file twice
+adds nothing the second time.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(args[0]); err != nil {
+			return &core.AppError{Msg: "cannot read " + args[0], Err: err}
+		}
+		incoming, err := newPinStore(args[0]).Load()
+		if err != nil {
+			return &core.AppError{Msg: "cannot import " + args[0], Err: err}
+		}
+
+		entries, s, err := loadEntries()
+		if err != nil {
+			return err
+		}
+		added, skipped := newEntries(entries, incoming)
+		// One write for the whole file, however many entries it holds.
+		if err := s.AppendAll(added); err != nil {
+			return err
+		}
+		fmt.Printf("imported %d %s", len(added), plural(len(added), "entry", "entries"))
+		if skipped > 0 {
+			fmt.Printf(" (%d already pinned)", skipped)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// newEntries returns the entries of incoming that entries does not hold
+// already, by id or by text, and how many were left out. Entries without
+// an id get a new one, and without a type the one InferType gives.
+func newEntries(entries, incoming []PinEntry) ([]PinEntry, int) {
+	ids := make(map[string]bool, len(entries))
+	texts := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		ids[e.ID], texts[e.Text] = true, true
+	}
+	var added []PinEntry
+	for _, e := range incoming {
+		if ids[e.ID] || texts[e.Text] {
+			continue
+		}
+		if e.ID == "" {
+			created := e.CreatedAt
+			e.Entry = store.NewEntry()
+			if !created.IsZero() {
+				e.CreatedAt = created
+			}
+		}
+		if e.Type == "" {
+			e.Type = InferType(e.Text)
+		}
+		ids[e.ID], texts[e.Text] = true, true
+		added = append(added, e)
+	}
+	return added, len(incoming) - len(added)
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	store "github.com/reky0/glyph-store"
+)
+
+func TestNewEntries(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []PinEntry{
+		pinned("1", "git log --oneline"),
+		pinned("2", "https://go.dev"),
+	}
+	incoming := []PinEntry{
+		pinned("1", "git log --oneline"), // same entry
+		pinned("9", "https://go.dev"),    // same text, other id
+		pinned("3", "kubectl get pods"),  // new
+		pinned("3", "kubectl get nodes"), // id repeated within the file
+		{Entry: store.Entry{CreatedAt: created}, Text: "https://example.com"},
+	}
+	incoming[2].Type = "cmd"
+
+	added, skipped := newEntries(entries, incoming)
+	if skipped != 3 {
+		t.Errorf("skipped = %d, want 3", skipped)
+	}
+	var texts []string
+	for _, e := range added {
+		texts = append(texts, e.Text)
+	}
+	if want := []string{"kubectl get pods", "https://example.com"}; !reflect.DeepEqual(texts, want) {
+		t.Fatalf("added %q, want %q", texts, want)
+	}
+	if added[0].ID != "3" || added[0].Type != "cmd" {
+		t.Errorf("kept entry = %+v, want its id and type unchanged", added[0])
+	}
+	if e := added[1]; e.ID == "" || !e.CreatedAt.Equal(created) || e.Type != "url" {
+		t.Errorf("entry without an id = %+v, want a new id, its date kept and type url", e)
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	core "github.com/reky0/glyph-core"
+	"github.com/spf13/cobra"
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link <id> <id>",
+	Short: "Mark two entries as related, such as a command and its docs",
+	Long: `Mark two entries as related. Links go both ways: pin show lists each
+entry under the other. Linking entries that are already linked does
+nothing.`,
+	Args: cobra.ExactArgs(2),
+
+	ValidArgsFunction: completeIDPair,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, s, err := loadEntries()
+		if err != nil {
+			return err
+		}
+		a, i, err := findByID(entries, args[0])
+		if err != nil {
+			return err
+		}
+		b, j, err := findByID(entries, args[1])
+		if err != nil {
+			return err
+		}
+		if i == j {
+			return &core.AppError{Msg: "cannot link an entry to itself"}
+		}
+		if slices.Contains(a.Related, b.ID) && slices.Contains(b.Related, a.ID) {
+			fmt.Printf("%s and %s are already linked\n", shortID(a.ID), shortID(b.ID))
+			return nil
+		}
+		entries[i].Related = addRelated(a.Related, b.ID)
+		entries[j].Related = addRelated(b.Related, a.ID)
+		if err := s.Save(entries); err != nil {
+			return err
+		}
+		fmt.Printf("linked %s and %s\n", shortID(a.ID), shortID(b.ID))
+		return nil
+	},
+}
+
+// addRelated returns related with id added, unless it is already there.
+func addRelated(related []string, id string) []string {
+	if slices.Contains(related, id) {
+		return related
+	}
+	return append(related, id)
+}
+
+// unlinkAll removes id from the related entries of every entry, for when
+// the entry with that id is removed.
+func unlinkAll(entries []PinEntry, id string) {
+	for i := range entries {
+		entries[i].Related = slices.DeleteFunc(entries[i].Related, func(r string) bool { return r == id })
+		if len(entries[i].Related) == 0 {
+			entries[i].Related = nil
+		}
+	}
+}
+
+// deleteEntries returns entries without those del matches, and how many
+// it removed. The removed entries are unlinked from the ones kept, so no
+// link is left pointing at an entry that is gone. rm, clear and gc all
+// delete through it.
+func deleteEntries(entries []PinEntry, del func(e PinEntry) bool) ([]PinEntry, int) {
+	kept := make([]PinEntry, 0, len(entries))
+	var removed []string
+	for _, e := range entries {
+		if del(e) {
+			removed = append(removed, e.ID)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	for _, id := range removed {
+		unlinkAll(kept, id)
+	}
+	return kept, len(removed)
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeleteEntries(t *testing.T) {
+	linked := func(id string, related ...string) PinEntry {
+		e := pinned(id, "text "+id)
+		e.Related = related
+		return e
+	}
+	entries := []PinEntry{
+		linked("a", "b", "c"),
+		linked("b", "a"),
+		linked("c", "a", "d"),
+		linked("d", "c"),
+	}
+	tests := []struct {
+		name string
+		del  map[string]bool
+		want []PinEntry
+	}{
+		{"none", nil, entries},
+		{"one", map[string]bool{"a": true}, []PinEntry{
+			linked("b"),
+			linked("c", "d"),
+			linked("d", "c"),
+		}},
+		{"several", map[string]bool{"a": true, "d": true}, []PinEntry{
+			linked("b"),
+			linked("c"),
+		}},
+		{"all", map[string]bool{"a": true, "b": true, "c": true, "d": true}, []PinEntry{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// deleteEntries unlinks in place, so give it a copy.
+			var in []PinEntry
+			for _, e := range entries {
+				e.Related = append([]string(nil), e.Related...)
+				in = append(in, e)
+			}
+			kept, removed := deleteEntries(in, func(e PinEntry) bool { return tt.del[e.ID] })
+			if removed != len(tt.del) {
+				t.Errorf("removed %d, want %d", removed, len(tt.del))
+			}
+			if !reflect.DeepEqual(kept, tt.want) {
+				t.Errorf("kept =\n%+v\nwant\n%+v", kept, tt.want)
+			}
+		})
+	}
+}
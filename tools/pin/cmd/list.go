@@ -1,51 +1,132 @@
 package cmd
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
 	ink "github.com/reky0/glyph-ink"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// textColumn is the index of the TEXT column in the list table.
+const textColumn = 4
+
+// defaultTextWidth is where list cuts the TEXT column when neither --width
+// nor the terminal gives a width to fit.
+const defaultTextWidth = 60
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List pinned entries",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filterTag, _ := cmd.Flags().GetString("tag")
 		filterType, _ := cmd.Flags().GetString("type")
+		starredOnly, _ := cmd.Flags().GetBool("starred")
+		includeExpired, _ := cmd.Flags().GetBool("include-expired")
+		null, _ := cmd.Flags().GetBool("null")
+		showMeta, _ := cmd.Flags().GetStringSlice("show-meta")
+		metaPairs, _ := cmd.Flags().GetStringArray("meta")
+		width, _ := cmd.Flags().GetInt("width")
+		wrap, _ := cmd.Flags().GetBool("wrap")
+		if width < 0 {
+			return &core.AppError{Msg: fmt.Sprintf("invalid --width %d: it must be positive", width)}
+		}
+		if width == 0 {
+			width = ink.TerminalWidth()
+		}
+		metaFilter, err := parseMeta(metaPairs)
+		if err != nil {
+			return err
+		}
 
 		entries, _, err := loadEntries()
 		if err != nil {
 			return err
 		}
 
+		// Favorites first; the stable sort keeps insertion order otherwise.
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Favorite && !entries[j].Favorite
+		})
+
 		theme := ink.ThemeFrom(viper.GetString("style"))
-		tbl := theme.Table().Headers("ID", "TYPE", "TAG", "TEXT", "DATE")
+		headers := []string{"", "ID", "TYPE", "TAG", "TEXT"}
+		for _, key := range showMeta {
+			headers = append(headers, strings.ToUpper(key))
+		}
+		// The TEXT column gets whatever the other columns leave; without a
+		// known width it is cut at a fixed length instead.
+		tbl := theme.Table().Headers(append(headers, "DATE")...)
+		if wrap {
+			tbl.FitWrap(textColumn, width)
+		} else {
+			tbl.Fit(textColumn, width)
+		}
 
+		now := time.Now()
 		for _, e := range entries {
+			if !includeExpired && e.Expired(now) {
+				continue
+			}
+			if starredOnly && !e.Favorite {
+				continue
+			}
 			if filterTag != "" && e.Tag != filterTag {
 				continue
 			}
 			if filterType != "" && e.Type != filterType {
 				continue
 			}
-			tbl.Row(
-				shortID(e.ID),
-				e.Type,
-				e.Tag,
-				truncate(e.Text, 60),
-				e.CreatedAt.Format(time.DateOnly),
-			)
+			if !matchesMeta(e, metaFilter) {
+				continue
+			}
+			if null {
+				fmt.Print(selectorRecord(e))
+				continue
+			}
+			star := ""
+			if e.Favorite {
+				star = starMarker
+			}
+			text := e.Text
+			if width == 0 {
+				text = truncate(text, defaultTextWidth)
+			}
+			if e.Type == "url" && ink.HyperlinksSupported() {
+				// Clickable even when truncated; elsewhere the full URL
+				// would only widen the table.
+				text = theme.Link(text, e.Text)
+			}
+			row := []string{star, shortID(e.ID), typeBadge(theme, e.Type), e.Tag, text}
+			for _, key := range showMeta {
+				row = append(row, e.Meta[key])
+			}
+			tbl.Row(append(row, e.CreatedAt.Format(time.DateOnly))...)
 		}
 
-		tbl.RenderToStdout()
+		if !null {
+			cli.Page(tbl.String())
+		}
 		return nil
 	},
 }
 
 func init() {
 	listCmd.Flags().String("tag", "", "Filter by tag")
-	listCmd.Flags().String("type", "", "Filter by type: url, cmd, note")
+	listCmd.Flags().String("type", "", "Filter by type: "+strings.Join(entryTypes, ", "))
+	listCmd.Flags().Bool("starred", false, "Only show starred entries")
+	listCmd.Flags().Bool("null", false, "Print entries NUL-separated for fzf --read0 instead of a table (see get-selected)")
+	listCmd.Flags().Bool("include-expired", false, "Also show entries whose --ttl has passed")
+	listCmd.Flags().StringArray("meta", nil, "Only show entries with this key=value field (repeatable)")
+	listCmd.Flags().Int("width", 0, "Fit the table to this many columns, truncating TEXT (default: the terminal width)")
+	listCmd.Flags().Bool("wrap", false, "Wrap TEXT onto more lines instead of truncating it to fit the width")
+	listCmd.Flags().StringSlice("show-meta", nil, "Add a column for each of these meta keys, e.g. project,priority")
+	_ = listCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	_ = listCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(entryTypes, cobra.ShellCompDirectiveNoFileComp))
 	rootCmd.AddCommand(listCmd)
 }
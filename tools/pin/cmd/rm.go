@@ -10,17 +10,19 @@ var rmCmd = &cobra.Command{
 	Use:   "rm <id>",
 	Short: "Remove an entry",
 	Args:  cobra.ExactArgs(1),
+
+	ValidArgsFunction: completeIDs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		entries, s, err := loadEntries()
 		if err != nil {
 			return err
 		}
-		_, idx, err := findByID(entries, args[0])
+		e, _, err := findByID(entries, args[0])
 		if err != nil {
 			return err
 		}
-		updated := append(entries[:idx], entries[idx+1:]...)
-		if err := s.Save(updated); err != nil {
+		kept, _ := deleteEntries(entries, func(x PinEntry) bool { return x.ID == e.ID })
+		if err := s.Save(kept); err != nil {
 			return err
 		}
 		fmt.Printf("removed %s\n", args[0])
@@ -3,9 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	cli "github.com/reky0/glyph-cli"
+	core "github.com/reky0/glyph-core"
+	ink "github.com/reky0/glyph-ink"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // Version is injected at build time via ldflags.
@@ -15,6 +18,14 @@ var rootCmd = &cobra.Command{
 	Use:     "pin",
 	Short:   "Clipboard for things you find in the terminal",
 	Version: Version,
+	// pin works without a config file; one is read only for its [theme]
+	// options, and an unreadable one is ignored.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if cfg, err := core.LoadConfig(); err == nil {
+			cli.ApplyTheme(cfg)
+		}
+		return nil
+	},
 }
 
 func Execute() {
@@ -25,8 +36,15 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().String("style", "rounded", "Output style: ascii, rounded, minimal")
-	if err := viper.BindPFlag("style", rootCmd.PersistentFlags().Lookup("style")); err != nil {
-		panic(fmt.Sprintf("failed to bind style flag: %v", err))
-	}
+	rootCmd.PersistentFlags().String("style", "auto", "Output style: "+strings.Join(ink.ThemeNames(), ", "))
+	rootCmd.PersistentFlags().StringVar(&collection, "collection", "", "Use a separate pin collection, e.g. work (default: the main one)")
+	cli.BindFlags(rootCmd, map[string]string{"style": "style"})
+	cli.AddRawFlag(rootCmd)
+	cli.AddPagerFlag(rootCmd)
+	rootCmd.AddCommand(cli.VersionCommand(Version), cli.CompletionCommand(), cli.ThemeCommand(), cli.ConfigCommand())
+}
+
+// Command returns the root command, for embedding in the glyph binary.
+func Command() *cobra.Command {
+	return rootCmd
 }
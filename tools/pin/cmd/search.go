@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	cli "github.com/reky0/glyph-cli"
 	ink "github.com/reky0/glyph-ink"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -15,6 +16,7 @@ var searchCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := strings.ToLower(args[0])
+		includeExpired, _ := cmd.Flags().GetBool("include-expired")
 		entries, _, err := loadEntries()
 		if err != nil {
 			return err
@@ -23,12 +25,16 @@ var searchCmd = &cobra.Command{
 		theme := ink.ThemeFrom(viper.GetString("style"))
 		tbl := theme.Table().Headers("ID", "TYPE", "TAG", "TEXT", "DATE")
 
+		now := time.Now()
 		for _, e := range entries {
+			if !includeExpired && e.Expired(now) {
+				continue
+			}
 			if strings.Contains(strings.ToLower(e.Text), query) ||
 				strings.Contains(strings.ToLower(e.Tag), query) {
 				tbl.Row(
 					shortID(e.ID),
-					e.Type,
+					typeBadge(theme, e.Type),
 					e.Tag,
 					truncate(e.Text, 60),
 					e.CreatedAt.Format(time.DateOnly),
@@ -36,11 +42,12 @@ var searchCmd = &cobra.Command{
 			}
 		}
 
-		tbl.RenderToStdout()
+		cli.Page(tbl.String())
 		return nil
 	},
 }
 
 func init() {
+	searchCmd.Flags().Bool("include-expired", false, "Also search entries whose --ttl has passed")
 	rootCmd.AddCommand(searchCmd)
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	core "github.com/reky0/glyph-core"
+	"github.com/spf13/cobra"
+)
+
+// selectorRecord formats e for list --null: the full ID, type, tag and
+// text, tab-separated and NUL-terminated, so multi-line text stays one
+// item in fzf --read0. get-selected reads the ID back from the start.
+func selectorRecord(e PinEntry) string {
+	return e.ID + "\t" + e.Type + "\t" + e.Tag + "\t" + e.Text + "\x00"
+}
+
+var getSelectedCmd = &cobra.Command{
+	Use:   "get-selected",
+	Short: "Print the entries picked from list --null in fzf",
+	Long: `Read what fzf printed for items of pin list --null from stdin and print
+the text of each entry, as get does:
+
+  pin list --null | fzf --read0 --delimiter '\t' --with-nth 2.. | pin get-selected
+
+With fzf --print0 (and -m to pick several), each NUL-separated item is an
+entry, printed one per line; otherwise stdin is a single item.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		items := []string{string(data)}
+		if bytes.IndexByte(data, 0) >= 0 {
+			items = strings.Split(string(data), "\x00")
+		}
+
+		s, err := openStore()
+		if err != nil {
+			return err
+		}
+		var texts []string
+		for _, item := range items {
+			id, _, ok := strings.Cut(strings.TrimLeft(item, "\n"), "\t")
+			if !ok {
+				if strings.TrimSpace(item) == "" {
+					continue
+				}
+				return &core.AppError{Msg: fmt.Sprintf("%q is not an item of pin list --null", truncate(item, 40))}
+			}
+			entry, err := lookupEntry(s, id)
+			if err != nil {
+				return err
+			}
+			texts = append(texts, entry.Text)
+		}
+		if len(texts) == 0 {
+			return &core.AppError{Msg: "nothing selected"}
+		}
+		fmt.Print(strings.Join(texts, "\n"))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getSelectedCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	cli "github.com/reky0/glyph-cli"
+	ink "github.com/reky0/glyph-ink"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show every field of an entry, with its full text",
+	Long: `Show every field of an entry: its full id, type, tag, dates, meta
+fields and the entries linked to it (see pin link), followed by its full
+text. A URL is clickable on terminals that support hyperlinks.`,
+	Args: cobra.ExactArgs(1),
+
+	ValidArgsFunction: completeIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, _, err := loadEntries()
+		if err != nil {
+			return err
+		}
+		entry, _, err := findByID(entries, args[0])
+		if err != nil {
+			return err
+		}
+		theme := ink.ThemeFrom(viper.GetString("style"))
+		cli.Page(renderEntry(theme, entry, relatedEntries(entries, entry)))
+		return nil
+	},
+}
+
+// renderEntry lays out every field of e, then its full text.
+func renderEntry(theme ink.Theme, e PinEntry, related []PinEntry) string {
+	kv := ink.NewKV(theme).
+		Add("ID", e.ID).
+		Add("Type", typeBadge(theme, e.Type))
+	if e.Tag != "" {
+		kv.Add("Tag", e.Tag)
+	}
+	if e.Favorite {
+		kv.Add("Starred", starMarker)
+	}
+	kv.Add("Created", e.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if !e.ExpiresAt.IsZero() {
+		expires := e.ExpiresAt.Local().Format("2006-01-02 15:04")
+		if e.Expired(time.Now()) {
+			expires += " " + theme.Muted("(expired)")
+		}
+		kv.Add("Expires", expires)
+	}
+	keys := make([]string, 0, len(e.Meta))
+	for key := range e.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		kv.Add(key, e.Meta[key])
+	}
+	if len(related) > 0 {
+		lines := make([]string, len(related))
+		for i, r := range related {
+			lines[i] = shortID(r.ID) + "  " + typeBadge(theme, r.Type) + "  " + truncate(firstLine(r.Text), 50)
+		}
+		kv.Add("Related", strings.Join(lines, "\n"))
+	}
+
+	text := strings.TrimRight(e.Text, "\n")
+	if e.Type == "url" {
+		text = theme.Link(text, text)
+	}
+	return kv.String() + "\n" + text + "\n"
+}
+
+// firstLine returns s up to its first newline, for one-line previews.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// relatedEntries returns the entries e is linked to, in the order they
+// were linked. Links to entries that no longer exist are skipped.
+func relatedEntries(entries []PinEntry, e PinEntry) []PinEntry {
+	var related []PinEntry
+	for _, id := range e.Related {
+		for _, r := range entries {
+			if r.ID == id {
+				related = append(related, r)
+				break
+			}
+		}
+	}
+	return related
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
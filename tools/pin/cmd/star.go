@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// starMarker flags favorite entries in tables.
+const starMarker = "★"
+
+var starCmd = &cobra.Command{
+	Use:   "star <id>",
+	Short: "Mark an entry as a favorite so it is listed first",
+	Args:  cobra.ExactArgs(1),
+
+	ValidArgsFunction: completeIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setFavorite(args[0], true)
+	},
+}
+
+var unstarCmd = &cobra.Command{
+	Use:   "unstar <id>",
+	Short: "Remove an entry from the favorites",
+	Args:  cobra.ExactArgs(1),
+
+	ValidArgsFunction: completeIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setFavorite(args[0], false)
+	},
+}
+
+// setFavorite resolves id (full or short) and stores the favorite flag.
+func setFavorite(id string, favorite bool) error {
+	entries, s, err := loadEntries()
+	if err != nil {
+		return err
+	}
+	e, _, err := findByID(entries, id)
+	if err != nil {
+		return err
+	}
+	found, err := s.Update(e.ID, func(p *PinEntry) { p.Favorite = favorite })
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no entry with id %q", id)
+	}
+	if favorite {
+		fmt.Printf("starred %s\n", shortID(e.ID))
+	} else {
+		fmt.Printf("unstarred %s\n", shortID(e.ID))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(starCmd)
+	rootCmd.AddCommand(unstarCmd)
+}
@@ -2,19 +2,56 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	core "github.com/reky0/glyph-core"
 	store "github.com/reky0/glyph-store"
 )
 
+// pinKeyEnv names the environment variable holding the passphrase used to
+// encrypt the pin data file at rest. When unset, pins are stored as plain JSON.
+const pinKeyEnv = "GLYPH_PIN_KEY"
+
+// collection is the --collection flag: the name of the pin collection to
+// use, or "" for the default one.
+var collection string
+
+// collectionName matches valid collection names, which become part of a
+// file name.
+var collectionName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// collectionFile returns the data file name of collection name: pins.json
+// for the default collection and pins-<name>.json for the others.
+func collectionFile(name string) string {
+	if name == "" {
+		return "pins.json"
+	}
+	return "pins-" + name + ".json"
+}
+
 func openStore() (*store.Store[PinEntry], error) {
+	if collection != "" && !collectionName.MatchString(collection) {
+		return nil, &core.AppError{Msg: fmt.Sprintf("invalid collection name %q: use letters, digits, - and _", collection)}
+	}
 	paths := core.NewPaths("pin")
 	dir, err := paths.DataDir()
 	if err != nil {
 		return nil, err
 	}
-	return store.NewStore[PinEntry](filepath.Join(dir, "pins.json")), nil
+	return newPinStore(filepath.Join(dir, collectionFile(collection))), nil
+}
+
+// newPinStore returns the store for the pin file at path, encrypted when
+// GLYPH_PIN_KEY is set. Whoever sets a key wants the pins kept private, so
+// the encrypted file is also only readable by its owner.
+func newPinStore(path string) *store.Store[PinEntry] {
+	if key := os.Getenv(pinKeyEnv); key != "" {
+		return store.NewEncryptedStore[PinEntry](path, []byte(key)).WithPerm(0o600)
+	}
+	return store.NewStore[PinEntry](path)
 }
 
 func loadEntries() ([]PinEntry, *store.Store[PinEntry], error) {
@@ -29,11 +66,64 @@ func loadEntries() ([]PinEntry, *store.Store[PinEntry], error) {
 	return entries, s, nil
 }
 
+// maxAmbiguousListed bounds the candidates an ambiguous id error lists.
+const maxAmbiguousListed = 5
+
+// findByID returns the entry with the full ID id, or else the one entry
+// whose ID starts with id, which must be at least as long as a short ID.
+// Entries pinned within the same couple of minutes share their short ID,
+// so a prefix matching several entries is an error listing them, rather
+// than a guess.
 func findByID(entries []PinEntry, id string) (PinEntry, int, error) {
+	var matches []int
 	for i, e := range entries {
-		if e.ID == id || shortID(e.ID) == id {
+		if e.ID == id {
 			return e, i, nil
 		}
+		if len(id) >= len(shortID(e.ID)) && strings.HasPrefix(e.ID, id) {
+			matches = append(matches, i)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return PinEntry{}, -1, fmt.Errorf("no entry with id %q", id)
+	case 1:
+		return entries[matches[0]], matches[0], nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiguous id %q, matches %d entries; use more of the id:", id, len(matches))
+	for n, i := range matches {
+		if n == maxAmbiguousListed {
+			fmt.Fprintf(&b, "\n  … and %d more", len(matches)-n)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s  %s", entries[i].ID, truncate(entries[i].Text, 50))
+	}
+	return PinEntry{}, -1, &core.AppError{Msg: b.String()}
+}
+
+// lookupEntry returns the entry of s that id names: the one with that full
+// ID, as the store's Get finds it, or else the entry findByID matches by a
+// prefix such as a short ID.
+func lookupEntry(s *store.Store[PinEntry], id string) (PinEntry, error) {
+	if e, ok, err := s.Get(id); err != nil || ok {
+		return e, err
+	}
+	entries, err := s.Load()
+	if err != nil {
+		return PinEntry{}, err
+	}
+	e, _, err := findByID(entries, id)
+	return e, err
+}
+
+// findByText returns the first entry whose text equals text exactly.
+func findByText(entries []PinEntry, text string) (PinEntry, bool) {
+	for _, e := range entries {
+		if e.Text == text {
+			return e, true
+		}
 	}
-	return PinEntry{}, -1, fmt.Errorf("no entry with id %q", id)
+	return PinEntry{}, false
 }
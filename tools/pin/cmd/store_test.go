@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	store "github.com/reky0/glyph-store"
+)
+
+func pinned(id, text string) PinEntry {
+	return PinEntry{Entry: store.Entry{ID: id}, Text: text}
+}
+
+func TestFindByID(t *testing.T) {
+	entries := []PinEntry{
+		pinned("1792a3f0111111111", "git log --oneline"),
+		pinned("1792a3f0222222222", "https://go.dev"),
+		pinned("2045b7c0333333333", "kubectl get pods"),
+	}
+	// Seven entries pinned within the same couple of minutes.
+	var crowd []PinEntry
+	for i := range 7 {
+		crowd = append(crowd, pinned(fmt.Sprintf("30000000%09d", i), fmt.Sprintf("note %d", i)))
+	}
+
+	tests := []struct {
+		name    string
+		entries []PinEntry
+		id      string
+		want    int      // index of the entry found, -1 for an error
+		errHas  []string // in the error message
+		errNot  []string // not in the error message
+	}{
+		{name: "full id", entries: entries, id: "1792a3f0222222222", want: 1},
+		{name: "unique short id", entries: entries, id: "2045b7c0", want: 2},
+		{name: "unique longer prefix", entries: entries, id: "1792a3f01", want: 0},
+		{
+			name: "colliding short id", entries: entries, id: "1792a3f0", want: -1,
+			errHas: []string{`ambiguous id "1792a3f0", matches 2 entries`, "1792a3f0111111111  git log --oneline", "1792a3f0222222222  https://go.dev"},
+			errNot: []string{"2045b7c0"},
+		},
+		{
+			name: "more than five candidates", entries: crowd, id: "30000000", want: -1,
+			errHas: []string{"matches 7 entries", "30000000000000004  note 4", "… and 2 more"},
+			errNot: []string{"note 5", "note 6"},
+		},
+		{name: "shorter than a short id", entries: entries, id: "2045", want: -1, errHas: []string{`no entry with id "2045"`}},
+		{name: "no match", entries: entries, id: "99999999", want: -1, errHas: []string{`no entry with id "99999999"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, i, err := findByID(tt.entries, tt.id)
+			if tt.want >= 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i != tt.want || e.ID != tt.entries[tt.want].ID {
+					t.Errorf("found %d (%s), want %d", i, e.ID, tt.want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("found %d (%s), want an error", i, e.ID)
+			}
+			if i != -1 {
+				t.Errorf("index = %d with an error, want -1", i)
+			}
+			for _, s := range tt.errHas {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("error %q does not mention %q", err, s)
+				}
+			}
+			for _, s := range tt.errNot {
+				if strings.Contains(err.Error(), s) {
+					t.Errorf("error %q mentions %q", err, s)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPinStorePerm(t *testing.T) {
+	tests := []struct {
+		key  string
+		want os.FileMode
+	}{
+		{"", 0o644},
+		{"correct horse", 0o600},
+	}
+	for _, tt := range tests {
+		t.Setenv(pinKeyEnv, tt.key)
+		path := filepath.Join(t.TempDir(), "pins.json")
+		if err := newPinStore(path).Save([]PinEntry{pinned("1", "secret")}); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != tt.want {
+			t.Errorf("%s=%q: mode = %o, want %o", pinKeyEnv, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLookupEntry(t *testing.T) {
+	s := store.NewStore[PinEntry](filepath.Join(t.TempDir(), "pins.json"))
+	if err := s.Save([]PinEntry{
+		pinned("1792a3f0111111111", "git log --oneline"),
+		pinned("1792a3f0222222222", "https://go.dev"),
+		pinned("2045b7c0333333333", "kubectl get pods"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		id, want string // want "" for an error
+	}{
+		{"1792a3f0222222222", "https://go.dev"},
+		{"2045b7c0", "kubectl get pods"},
+		{"1792a3f0", ""},
+		{"99999999", ""},
+	}
+	for _, tt := range tests {
+		e, err := lookupEntry(s, tt.id)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("lookupEntry(%s) = %q, want an error", tt.id, e.Text)
+		case tt.want != "" && (err != nil || e.Text != tt.want):
+			t.Errorf("lookupEntry(%s) = %q, %v; want %q", tt.id, e.Text, err, tt.want)
+		}
+	}
+}
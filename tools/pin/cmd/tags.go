@@ -0,0 +1,17 @@
+package cmd
+
+import "sort"
+
+// usedTags returns the distinct tags of entries, sorted.
+func usedTags(entries []PinEntry) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, e := range entries {
+		if e.Tag != "" && !seen[e.Tag] {
+			seen[e.Tag] = true
+			tags = append(tags, e.Tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// bulletPattern matches a list item: a -, *, +, • or "1." / "1)" marker
+// followed by the item text.
+var bulletPattern = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])\s+(.*)$`)
+
+// inlineMarkdown matches the emphasis and code markers removed from items.
+var inlineMarkdown = strings.NewReplacer("**", "", "__", "", "`", "")
+
+// formatBullets rewrites a model's standup as a plain bullet list: prose
+// before the first item and after the last one is dropped, indented lines
+// continuing an item are joined to it, inline markdown is removed, every
+// item gets the marker bullet and at most max items are kept. It reports
+// false, and returns text unchanged, when text contains no list items.
+func formatBullets(text, bullet string, max int) (string, bool) {
+	var items []string
+	inItem := false
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if m := bulletPattern.FindStringSubmatch(line); m != nil {
+			items = append(items, m[1])
+			inItem = true
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			// A blank line or heading ends the current item; text after it
+			// is prose unless another item follows.
+			inItem = false
+			continue
+		}
+		// Only indented text continues an item; an unindented line such
+		// as "Hope this helps!" is prose.
+		if inItem && (raw[0] == ' ' || raw[0] == '\t') {
+			items[len(items)-1] += " " + line
+			continue
+		}
+		inItem = false
+	}
+	if len(items) == 0 {
+		return text, false
+	}
+	if max > 0 && len(items) > max {
+		items = items[:max]
+	}
+
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(bullet + " " + strings.TrimSpace(inlineMarkdown.Replace(item)))
+	}
+	return b.String(), true
+}
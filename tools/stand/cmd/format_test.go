@@ -0,0 +1,69 @@
+package cmd
+
+import "testing"
+
+func TestFormatBullets(t *testing.T) {
+	tests := []struct {
+		name, text string
+		max        int
+		want       string
+		ok         bool
+	}{
+		{
+			name: "preamble",
+			text: "Here is your standup:\n\n- fixed the login bug\n- reviewed PRs",
+			want: "• fixed the login bug\n• reviewed PRs",
+			ok:   true,
+		},
+		{
+			name: "trailing prose",
+			text: "- did X\nHope this helps!",
+			want: "• did X",
+			ok:   true,
+		},
+		{
+			name: "trailing prose after a blank line",
+			text: "- did X\n\nLet me know if you need more.",
+			want: "• did X",
+			ok:   true,
+		},
+		{
+			name: "indented continuation",
+			text: "- refactored the parser so that\n  errors point at the column\n- did Y",
+			want: "• refactored the parser so that errors point at the column\n• did Y",
+			ok:   true,
+		},
+		{
+			name: "numbered markers",
+			text: "1. wrote tests\n2) shipped v1.2",
+			want: "• wrote tests\n• shipped v1.2",
+			ok:   true,
+		},
+		{
+			name: "inline markdown",
+			text: "* **Fixed** the `retry` loop\n+ __merged__ #42",
+			want: "• Fixed the retry loop\n• merged #42",
+			ok:   true,
+		},
+		{
+			name: "max bullets",
+			text: "- one\n- two\n- three\n- four",
+			max:  2,
+			want: "• one\n• two",
+			ok:   true,
+		},
+		{
+			name: "no items",
+			text: "Nothing to report today.",
+			want: "Nothing to report today.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := formatBullets(tt.text, "•", tt.max)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("formatBullets = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
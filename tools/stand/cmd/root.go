@@ -1,17 +1,20 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"strings"
+	"time"
 
+	cli "github.com/reky0/glyph-cli"
 	core "github.com/reky0/glyph-core"
+	git "github.com/reky0/glyph-git"
 	ink "github.com/reky0/glyph-ink"
 	mind "github.com/reky0/glyph-mind"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -23,13 +26,35 @@ Given a list of git commit messages, write a short standup in first person.
 Format: 3-5 bullet points, plain English, no jargon, no markdown.
 Focus on what was done, not implementation details.`
 
+// defaultMaxCommits is the default of --max-commits: months of busy work,
+// but not the whole history a mistyped --since can pull in.
+const defaultMaxCommits = 200
+
+// defaultSaveName is the --save value used when the flag has no argument;
+// the date placeholder is filled in at run time.
+const defaultSaveName = "standup-YYYY-MM-DD.md"
+
 var rootCmd = &cobra.Command{
 	Use:     "stand",
 	Short:   "Generate a standup update from recent git activity",
 	Version: Version,
+	Args:    standArgs,
 	RunE:    runStand,
 }
 
+// standArgs rejects arguments. As --save takes an optional value, the file
+// in "--save notes.md" is an argument rather than the flag's value, so
+// that case gets an error showing the --save=notes.md form.
+func standArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if save := cmd.Flags().Lookup("save"); save.Changed && save.Value.String() == defaultSaveName {
+		return &core.AppError{Msg: fmt.Sprintf("unexpected argument %q; to save to that file, write --save=%s", args[0], args[0])}
+	}
+	return &core.AppError{Msg: fmt.Sprintf("unexpected argument %q; stand takes only flags (see stand --help)", args[0])}
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -38,80 +63,216 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().String("style", "rounded", "Output style: ascii, rounded, minimal")
+	rootCmd.PersistentFlags().String("style", "auto", "Output style: "+strings.Join(ink.ThemeNames(), ", "))
+	rootCmd.PersistentFlags().String("save", "", "Also write the standup to this file, given as --save=notes.md (--save alone uses "+defaultSaveName+")")
+	rootCmd.PersistentFlags().Lookup("save").NoOptDefVal = defaultSaveName
 	rootCmd.Flags().String("since", "today", "Date range: today, yesterday, '2 days ago', or any git-compatible date")
+	rootCmd.Flags().String("author", "", "Only commits whose author matches this pattern (default: your git user.email)")
+	rootCmd.Flags().Bool("all-authors", false, "Include everyone's commits, not only yours (also --no-author)")
+	rootCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "no-author" {
+			name = "all-authors"
+		}
+		return pflag.NormalizedName(name)
+	})
+	rootCmd.Flags().Int("max-commits", defaultMaxCommits, "Summarize at most this many of the newest commits (0 means no limit)")
 	rootCmd.Flags().Bool("copy", false, "Print a note to pipe output manually to clipboard")
-	if err := viper.BindPFlag("style", rootCmd.PersistentFlags().Lookup("style")); err != nil {
-		panic(fmt.Sprintf("failed to bind style flag: %v", err))
-	}
+	rootCmd.Flags().Bool("strict-format", false, "Wait for the whole answer and reduce it to a plain bullet list (see --bullet, --max-bullets)")
+	rootCmd.Flags().String("bullet", "-", "With --strict-format, the marker for each bullet")
+	rootCmd.Flags().Int("max-bullets", 5, "With --strict-format, the most bullets to keep (0 means no limit)")
+	cli.BindFlags(rootCmd, map[string]string{"style": "style"})
+	cli.AddAIFlags(rootCmd)
+	cli.AddRawFlag(rootCmd)
+	cli.AddQuietFlag(rootCmd)
+	rootCmd.AddCommand(cli.VersionCommand(Version), cli.CompletionCommand(), cli.ThemeCommand(), cli.ConfigCommand())
+	rootCmd.AddCommand(cli.ModelsCommand())
+	rootCmd.AddCommand(cli.HistoryCommand())
 }
 
 func runStand(cmd *cobra.Command, args []string) error {
 	theme := ink.ThemeFrom(viper.GetString("style"))
 	since, _ := cmd.Flags().GetString("since")
 	copyMode, _ := cmd.Flags().GetBool("copy")
+	strictFormat, _ := cmd.Flags().GetBool("strict-format")
 
-	commits, err := getCommits(since)
+	if err := git.RequireRepo(""); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	author, err := commitAuthor(cmd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	commits, err := getCommits(since, author)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
 	if strings.TrimSpace(commits) == "" {
-		fmt.Println(theme.Muted("No commits found since " + since + "."))
+		if author != "" {
+			fmt.Println(theme.Muted("No commits by " + author + " found since " + since + "."))
+		} else {
+			fmt.Println(theme.Muted("No commits found since " + since + "."))
+		}
 		return nil
 	}
+	if maxCommits, _ := cmd.Flags().GetInt("max-commits"); maxCommits > 0 {
+		var omitted int
+		if commits, omitted = capCommits(commits, maxCommits); omitted > 0 {
+			fmt.Fprintln(os.Stderr, theme.Muted(fmt.Sprintf(
+				"warning: %d commits since %s; only the newest %d are summarized (raise --max-commits to send more)",
+				maxCommits+omitted, since, maxCommits)))
+		}
+	}
 
-	cfg, err := core.LoadConfig()
+	cfg, err := cli.LoadConfig()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
-	if style := viper.GetString("style"); style != "" {
-		cfg.DefaultStyle = style
+
+	if err := cli.CheckPromptSize(cfg, standSystemPrompt, commits); err != nil {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
 	}
 
-	client, err := mind.NewClientFromConfig(cfg)
+	client, err := cli.NewClient(cfg)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
+	// A standup is read-only, so a failed start is safe to retry.
+	client = mind.WithRetry(client, cfg.Retries)
 
-	ch, err := client.Stream(context.Background(), standSystemPrompt, commits)
+	// Ctrl-C ends the standup early; the part already written is still saved.
+	ctx, stop := cli.InterruptContext(context.Background())
+	defer stop()
+	reqCtx, cancel := cli.RequestContext(ctx)
+	defer cancel()
+	spin := cli.StartSpinner(theme, "writing standup…", "reading commits…", "still writing…")
+	stream, err := client.Stream(reqCtx, standSystemPrompt, commits)
 	if err != nil {
+		spin.Stop()
+		if ctx.Err() != nil {
+			cli.ExitInterrupt(theme)
+		}
 		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
 		os.Exit(1)
 	}
 
-	printer := ink.NewStreamPrinter(os.Stdout)
-	if err := printer.PrintStream(ch); err != nil {
+	// --strict-format rewrites the answer, so it is collected before
+	// anything is shown.
+	out := io.Writer(os.Stdout)
+	if strictFormat {
+		out = io.Discard
+	}
+	printer := cli.NewStreamPrinter(out)
+	printer.Spinner = spin
+	output, err := printer.PrintStream(stream.C)
+	stop()
+	if err != nil {
 		return err
 	}
+	interrupted := ctx.Err() != nil
+	if err := stream.Err(); err != nil && !interrupted {
+		fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+		os.Exit(1)
+	}
+	if !interrupted {
+		cli.WarnTruncated(cfg, stream)
+		cli.WarnEmpty(cfg, stream, output)
+		cli.PrintStats(cfg, stream)
+		cli.RecordTranscript("stand", cfg, standSystemPrompt, commits, stream, output)
+	}
+	if strictFormat {
+		if output, err = printFormatted(cmd, output); err != nil {
+			return err
+		}
+	}
+
+	if savePath, _ := cmd.Flags().GetString("save"); savePath != "" {
+		if savePath == defaultSaveName {
+			savePath = "standup-" + time.Now().Format(time.DateOnly) + ".md"
+		}
+		if err := cli.SaveOutput(savePath, output); err != nil {
+			fmt.Fprintln(os.Stderr, theme.Error(err.Error()))
+			os.Exit(1)
+		}
+		cli.Note(theme, "saved to "+savePath)
+	}
 
-	if copyMode {
-		fmt.Fprintln(os.Stderr, theme.Muted("\nTip: pipe output to clipboard with: stand | pbcopy  (macOS) or  stand | xclip  (Linux)"))
+	if copyMode && !cli.Raw() {
+		cli.Note(theme, "\nTip: pipe output to clipboard with: stand | pbcopy  (macOS) or  stand | xclip  (Linux)")
+	}
+	if interrupted {
+		cli.ExitInterrupt(theme)
 	}
 	return nil
 }
 
-func getCommits(since string) (string, error) {
-	// Resolve "today" / "yesterday" to git-compatible values.
-	switch strings.ToLower(strings.TrimSpace(since)) {
-	case "today":
-		since = "midnight"
-	case "yesterday":
-		since = "yesterday midnight"
+// printFormatted applies --strict-format to output, prints the result like
+// a streamed answer and returns it. An answer without any bullets is
+// printed as is, with a warning.
+func printFormatted(cmd *cobra.Command, output string) (string, error) {
+	bullet, _ := cmd.Flags().GetString("bullet")
+	maxBullets, _ := cmd.Flags().GetInt("max-bullets")
+	formatted, ok := formatBullets(output, bullet, maxBullets)
+	if !ok {
+		theme := ink.ThemeFrom(viper.GetString("style"))
+		fmt.Fprintln(os.Stderr, theme.Muted("warning: the answer has no bullet points; showing it unchanged"))
 	}
 
-	// Get the author email from git config.
-	authorBytes, err := runGit("config", "user.email")
-	if err != nil {
-		// Proceed without --author filter if git config fails.
-		authorBytes = nil
+	ch := make(chan string, 1)
+	ch <- formatted
+	close(ch)
+	return cli.NewStreamPrinter(os.Stdout).PrintStream(ch)
+}
+
+// capCommits keeps the newest max of commits, one subject per line as
+// getCommits returns them, and notes how many older ones were left out.
+// It returns the kept list and that number.
+func capCommits(commits string, max int) (string, int) {
+	lines := strings.Split(commits, "\n")
+	if len(lines) <= max {
+		return commits, 0
+	}
+	omitted := len(lines) - max
+	kept := strings.Join(lines[:max], "\n")
+	return kept + fmt.Sprintf("\n(%d older commits not shown)", omitted), omitted
+}
+
+// commitAuthor returns the pattern getCommits filters authors by: the
+// --author flag, nothing with --all-authors, and otherwise the email of
+// the git user, or nothing when it is not set.
+func commitAuthor(cmd *cobra.Command) (string, error) {
+	author, _ := cmd.Flags().GetString("author")
+	allAuthors, _ := cmd.Flags().GetBool("all-authors")
+	switch {
+	case cmd.Flags().Changed("author") && allAuthors:
+		return "", &core.AppError{Msg: "--author cannot be combined with --all-authors"}
+	case cmd.Flags().Changed("author") && strings.TrimSpace(author) == "":
+		return "", &core.AppError{Msg: "--author needs a pattern, such as a name or an email"}
+	case cmd.Flags().Changed("author"):
+		return author, nil
+	case allAuthors:
+		return "", nil
+	}
+	// Proceed without an author filter if git config fails.
+	email, _ := git.Run("", "config", "user.email")
+	return strings.TrimSpace(string(email)), nil
+}
+
+// getCommits returns the subjects of the commits since since, newest
+// first, by authors matching author, or by anyone when it is "".
+func getCommits(since, author string) (string, error) {
+	// Resolve the dates glyph understands itself; pass anything else on
+	// to git, which knows more forms such as "last monday".
+	if t, err := core.ParseSince(since); err == nil {
+		since = t.Format(time.RFC3339)
 	}
-	author := strings.TrimSpace(string(authorBytes))
 
 	gitArgs := []string{
-		"log",
 		"--since=" + since,
 		"--pretty=format:%s",
 	}
@@ -119,27 +280,17 @@ func getCommits(since string) (string, error) {
 		gitArgs = append(gitArgs, "--author="+author)
 	}
 
-	out, err := runGit(gitArgs...)
+	out, err := git.Log("", gitArgs...)
 	if err != nil {
 		return "", &core.AppError{
-			Msg: "git log failed — are you inside a git repository?",
+			Msg: "git log failed",
 			Err: err,
 		}
 	}
 	return string(out), nil
 }
 
-func runGit(args ...string) ([]byte, error) {
-	cmd := exec.Command("git", args...)
-	var out, errBuf bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errBuf
-	if err := cmd.Run(); err != nil {
-		msg := errBuf.String()
-		if msg == "" {
-			msg = err.Error()
-		}
-		return nil, fmt.Errorf("%s", msg)
-	}
-	return out.Bytes(), nil
+// Command returns the root command, for embedding in the glyph binary.
+func Command() *cobra.Command {
+	return rootCmd
 }
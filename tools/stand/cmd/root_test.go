@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestStandArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--save"}, ""},
+		{[]string{"--save=notes.md"}, ""},
+		{[]string{"--save", "notes.md"}, "write --save=notes.md"},
+		{[]string{"--save=notes.md", "extra"}, "stand takes only flags"},
+		{[]string{"extra"}, "stand takes only flags"},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("save", "", "")
+		cmd.Flags().Lookup("save").NoOptDefVal = defaultSaveName
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		err := standArgs(cmd, cmd.Flags().Args())
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%q: %v", tt.args, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%q: err = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}